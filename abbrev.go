@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// expandAbbreviations replaces abbreviated long flags with their full names (see Options.AllowFlagAbbreviations),
+// e.g. "--verb=true" with "--verbose=true". Abbreviations that do not match any flag are left as-is (so that they
+// are reported by checkFlags), while an ErrAmbiguousFlag is returned for the first abbreviation that matches more
+// than one flag (the remaining arguments are still expanded). The expansion stops at the "--" terminator (or the
+// first positional argument, unless interspersed).
+func (c *Command) expandAbbreviations(args []string) ([]string, error) {
+	if !c.options().AllowFlagAbbreviations || c.options().StrictPOSIX {
+		return args, nil
+	}
+	var (
+		expanded = make([]string, 0, len(args))
+		firstErr error
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(expanded, args[i:]...), firstErr
+		case !strings.HasPrefix(arg, "-") || len(arg) < 2:
+			if !c.interspersed() {
+				return append(expanded, args[i:]...), firstErr
+			}
+			expanded = append(expanded, arg)
+			continue
+		case strings.HasPrefix(arg, "--"):
+			full, err := c.expandAbbreviation(arg)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			arg = full
+		}
+		expanded = append(expanded, arg)
+		if n := flagArgs(c.fs, append([]string{arg}, args[i+1:]...)); n > 1 {
+			expanded = append(expanded, args[i+1])
+			i++
+		}
+	}
+	return expanded, firstErr
+}
+
+// expandAbbreviation expands a single long flag (with an optional "=value").
+func (c *Command) expandAbbreviation(arg string) (string, error) {
+	name, value, hasValue := strings.Cut(arg[2:], "=")
+	help := c.helpFlag()
+	if name == "" || c.fs.Lookup(name) != nil || (help.name != "" && name == help.name) {
+		return arg, nil
+	}
+
+	var candidates []string
+	if help.name != "" && c.fs.Lookup(help.name) == nil && strings.HasPrefix(help.name, name) {
+		candidates = append(candidates, help.name)
+	}
+	c.fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden && strings.HasPrefix(f.Name, name) {
+			candidates = append(candidates, f.Name)
+		}
+	})
+	switch len(candidates) {
+	case 0:
+		return arg, nil
+	case 1:
+		name = candidates[0]
+	default:
+		sort.Strings(candidates)
+		for i, candidate := range candidates {
+			candidates[i] = "--" + candidate
+		}
+		return arg, &ErrAmbiguousFlag{Flag: "--" + name, Candidates: candidates, tr: c.tr}
+	}
+	if hasValue {
+		return "--" + name + "=" + value, nil
+	}
+	return "--" + name, nil
+}
@@ -0,0 +1,112 @@
+package cli_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestAllowFlagAbbreviations(t *testing.T) {
+	tests := []struct {
+		description        string
+		args               []string
+		strict             bool
+		expectedVerbose    bool
+		expectedRegion     string
+		expectedArgs       []string
+		expectedCandidates []string
+		expectedErr        bool
+	}{
+		{
+			description:     "unambiguous prefix",
+			args:            []string{"deploy", "--verb"},
+			expectedVerbose: true,
+		},
+		{
+			description:    "global flag before subcommand",
+			args:           []string{"--reg", "eu-west-1", "deploy"},
+			expectedRegion: "eu-west-1",
+		},
+		{
+			description:    "assigned value",
+			args:           []string{"deploy", "--reg=eu-west-1"},
+			expectedRegion: "eu-west-1",
+		},
+		{
+			description:     "exact match takes precedence",
+			args:            []string{"deploy", "--ver", "--verbose"},
+			expectedVerbose: true,
+		},
+		{
+			description:        "ambiguous prefix",
+			args:               []string{"deploy", "--v"},
+			expectedCandidates: []string{"--ver", "--verbose", "--version"},
+			expectedErr:        true,
+		},
+		{
+			description:  "arguments after terminator",
+			args:         []string{"deploy", "--", "--verb"},
+			expectedArgs: []string{"--verb"},
+		},
+		{
+			description: "hidden flag",
+			args:        []string{"deploy", "--cpu", "cpu.out"},
+			expectedErr: true,
+		},
+		{
+			description: "strict mode",
+			args:        []string{"deploy", "--verb"},
+			strict:      true,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				verbose bool
+				region  string
+				args    []string
+			)
+			c := cli.Command{
+				Usage:   "app [command]",
+				Version: "1.0.0",
+				Flags:   []cli.Flag{&cli.StringFlag{Name: "region"}},
+				Opts: cli.Options{
+					ProfilingFlags:         true,
+					AllowFlagAbbreviations: true,
+					StrictPOSIX:            tc.strict,
+					ErrWriter:              io.Discard,
+				},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "deploy [flags] [args...]",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{Name: "verbose"},
+							&cli.BoolFlag{Name: "ver"},
+						},
+						Exec: func(c *cli.Context) error {
+							verbose, _ = c.GetBool("verbose")
+							region, _ = c.GetString("region")
+							args = c.Args()
+							return nil
+						},
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			eq(t, tc.expectedErr, err != nil)
+
+			var e *cli.ErrAmbiguousFlag
+			if errors.As(err, &e) {
+				eq(t, tc.expectedCandidates, e.Candidates)
+			}
+			eq(t, tc.expectedCandidates != nil, e != nil)
+			eq(t, tc.expectedVerbose, verbose)
+			eq(t, tc.expectedRegion, region)
+			eq(t, tc.expectedArgs, append([]string(nil), args...))
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// splitArgs walks the arguments to find the subcommand selected by them, which is the first positional argument if
+// it matches the name of a subcommand. Flags known to the command are skipped along with their values, while unknown
+// flags are assumed to belong to a subcommand and to not take a separate value. When a subcommand is found, it is
+// returned along with the index of its name in the arguments. Arguments after the "--" terminator are never matched
+// against subcommands.
+func (c *Command) splitArgs(args []string) (*Command, int) {
+	abbreviated, _ := c.expandAbbreviations(args) // Errors are returned when parsing the flags.
+	i := positionalIndex(c.fs, abbreviated)
+	if i < 0 {
+		return nil, -1
+	}
+	for _, s := range c.subcommands() {
+		if s.name() == args[i] {
+			return s, i
+		}
+	}
+	return nil, -1
+}
+
+// checkValidArgs returns an error if the first positional argument is not one of the ValidArgs of the command.
+func (c *Command) checkValidArgs() error {
+	if len(c.ValidArgs) == 0 || c.fs.NArg() == 0 {
+		return nil
+	}
+	arg := c.fs.Arg(0)
+	for _, v := range c.ValidArgs {
+		if v == arg {
+			return nil
+		}
+	}
+	return fmt.Errorf(c.tr("invalid argument %q for %q, expected one of: %s"), arg, c.path(), strings.Join(c.ValidArgs, ", "))
+}
+
+// positionalIndex returns the index of the first positional argument, or -1 if there are none before the "--"
+// terminator. Flags known to fs are skipped along with their values, while unknown flags are assumed to not take a
+// separate value.
+func positionalIndex(fs *pflag.FlagSet, args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if n := flagArgs(fs, args[i:]); n > 1 {
+				i += n - 1
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// expandAlias replaces the first positional argument with the arguments of the alias it names (if any). Aliases are
+// only expanded once, i.e. an alias cannot refer to another alias.
+func (c *Command) expandAlias(args []string) ([]string, error) {
+	i := positionalIndex(c.fs, args)
+	if i < 0 {
+		return args, nil
+	}
+	alias, ok := c.Opts.Aliases[args[i]]
+	if !ok {
+		return args, nil
+	}
+	expansion, err := splitQuoted(alias)
+	if err != nil {
+		return nil, fmt.Errorf("alias %q: %w", args[i], err)
+	}
+	expanded := append(args[:i:i], expansion...)
+	return append(expanded, args[i+1:]...), nil
+}
+
+// splitQuoted splits s into words separated by whitespace. Single and double quotes can be used to group words, and
+// a backslash escapes the next character (except within single quotes).
+func splitQuoted(s string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// flagArgs returns the number of arguments that make up the flag at the start of args (i.e. 1, or 2 when the value
+// is given as a separate argument), or 0 if the flag (or any of the shorthands in a group) is not known to fs.
+func flagArgs(fs *pflag.FlagSet, args []string) int {
+	arg := args[0]
+
+	if strings.HasPrefix(arg, "--") {
+		name := arg[2:]
+		if i := strings.Index(name, "="); i >= 0 {
+			name = name[:i]
+			if fs.Lookup(name) == nil {
+				return 0
+			}
+			return 1
+		}
+		f := fs.Lookup(name)
+		switch {
+		case f == nil:
+			return 0
+		case f.NoOptDefVal == "" && len(args) > 1:
+			return 2
+		default:
+			return 1
+		}
+	}
+
+	shorthands := arg[1:]
+	for i := 0; i < len(shorthands); i++ {
+		if shorthands[i] == '=' && i > 0 {
+			return 1 // Value for the preceding boolean-like flag, e.g. "-v=false".
+		}
+		f := fs.ShorthandLookup(shorthands[i : i+1])
+		switch {
+		case f == nil:
+			return 0
+		case f.NoOptDefVal != "":
+			continue // Boolean-like flags can be grouped, e.g. "-abc".
+		case i < len(shorthands)-1:
+			return 1 // The rest of the group is the value, e.g. "-n3" or "-n=3".
+		case len(args) > 1:
+			return 2
+		default:
+			return 1
+		}
+	}
+	return 1
+}
+
+// checkFlags returns an ErrUnknownFlag or ErrMissingFlagValue for the first flag in args that is not defined in fs
+// or is missing its value, so that these errors can be returned as types instead of the plain errors from pflag. It
+// returns pflag.ErrHelp if it finds the help flag (see Command.helpFlag), which is only used if it is not defined in
+// fs, and the arguments after it are not checked. When interspersed is false, checking stops at the first positional
+// argument (same as pflag). The errors use tr to translate their messages.
+func checkFlags(fs *pflag.FlagSet, args []string, interspersed bool, help helpFlag, tr func(string) string) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return nil
+		}
+		if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+			if !interspersed {
+				return nil
+			}
+			continue
+		}
+		if err := checkFlag(fs, args[i:], help, tr); err != nil {
+			return err
+		}
+		if n := flagArgs(fs, args[i:]); n > 1 {
+			i += n - 1
+		}
+	}
+	return nil
+}
+
+// checkFlag checks the flag (or group of shorthands) at the start of args. It returns pflag.ErrHelp if the flag is
+// an undefined help flag.
+func checkFlag(fs *pflag.FlagSet, args []string, help helpFlag, tr func(string) string) error {
+	arg := args[0]
+
+	if strings.HasPrefix(arg, "--") {
+		name := strings.SplitN(arg[2:], "=", 2)[0]
+		f := fs.Lookup(name)
+		switch {
+		case f == nil && help.name != "" && name == help.name:
+			return pflag.ErrHelp
+		case f == nil:
+			return &ErrUnknownFlag{Flag: "--" + name, tr: tr}
+		case f.NoOptDefVal == "" && !strings.Contains(arg, "=") && len(args) == 1:
+			return &ErrMissingFlagValue{Flag: arg, tr: tr}
+		}
+		return nil
+	}
+
+	shorthands := arg[1:]
+	for i := 0; i < len(shorthands); i++ {
+		if shorthands[i] == '=' && i > 0 {
+			return nil
+		}
+		f := fs.ShorthandLookup(shorthands[i : i+1])
+		switch {
+		case f == nil && help.shorthand != "" && shorthands[i:i+1] == help.shorthand:
+			return pflag.ErrHelp
+		case f == nil:
+			return &ErrUnknownFlag{Flag: "-" + shorthands[i:i+1], tr: tr}
+		case f.NoOptDefVal != "":
+			continue
+		case i == len(shorthands)-1 && len(args) == 1:
+			return &ErrMissingFlagValue{Flag: "-" + shorthands[i:i+1], tr: tr}
+		}
+		return nil // The rest of the group (or the next argument) is the value.
+	}
+	return nil
+}
+
+// helpFlag is the name and shorthand of the flag that requests help for a command. The name and shorthand are empty
+// when the help flag is disabled.
+type helpFlag struct {
+	name      string
+	shorthand string
+}
+
+// helpFlag returns the help flag of the command (see Options.HelpFlag and Command.DisableHelpFlag).
+func (c *Command) helpFlag() helpFlag {
+	if c.DisableHelpFlag {
+		return helpFlag{}
+	}
+	spec := c.options().HelpFlag
+	if spec == "" {
+		spec = "help, h"
+	}
+	name, shorthand := splitFlagName(spec)
+	return helpFlag{name: name, shorthand: shorthand}
+}
+
+// interspersed returns true if flags can be given after the positional arguments of the command.
+func (c *Command) interspersed() bool {
+	return !c.DisableInterspersed && !c.options().StrictPOSIX
+}
+
+// splitShorthands splits groups of shorthands into separate arguments before they are parsed, following the POSIX
+// conventions for short options: boolean-like flags (e.g. -d) can be grouped, and the first flag in a group that takes
+// a value uses the rest of the group as its value (with a leading "=" removed), or the next argument if it is last in
+// the group. E.g. with -t taking a value, "-dt3", "-dt=3" and "-dt 3" are all split into "-d", "-t" and "3". Boolean-like
+// flags can be given an explicit value using "=", e.g. "-d=false". Groups with shorthands that are not known to fs
+// are left as-is (so that they are reported by checkFlags), and splitting stops at the "--" terminator (or the first
+// positional argument, unless interspersed). When strict (see Options.StrictPOSIX), values are used verbatim and
+// boolean-like flags cannot be assigned a value, i.e. "-dt=3" is split into "-d", "-t" and "=3", and "-d=false" into
+// "-d" and "-=false" (which is an unknown flag).
+func splitShorthands(fs *pflag.FlagSet, args []string, interspersed, strict bool) []string {
+	var split []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(split, args[i:]...)
+		case !strings.HasPrefix(arg, "-") || len(arg) < 2:
+			if !interspersed {
+				return append(split, args[i:]...)
+			}
+			split = append(split, arg)
+			continue
+		}
+		if group, ok := splitShorthandGroup(fs, arg, strict); ok {
+			split = append(split, group...)
+		} else {
+			split = append(split, arg)
+		}
+		if n := flagArgs(fs, args[i:]); n > 1 {
+			split = append(split, args[i+1]) // The value of the (last) flag.
+			i++
+		}
+	}
+	return split
+}
+
+// splitShorthandGroup splits a group of shorthands (see splitShorthands), or returns false if the argument is not a
+// group of shorthands that are known to fs.
+func splitShorthandGroup(fs *pflag.FlagSet, arg string, strict bool) ([]string, bool) {
+	if strings.HasPrefix(arg, "--") {
+		return nil, false
+	}
+	var (
+		split      []string
+		shorthands = arg[1:]
+	)
+	for i := 0; i < len(shorthands); i++ {
+		f := fs.ShorthandLookup(shorthands[i : i+1])
+		if f == nil {
+			return nil, false
+		}
+		name, rest := "-"+shorthands[i:i+1], shorthands[i+1:]
+		switch {
+		case f.NoOptDefVal != "" && strings.HasPrefix(rest, "=") && strict:
+			return append(split, name, "-"+rest), true
+		case f.NoOptDefVal != "" && strings.HasPrefix(rest, "="):
+			return append(split, name+rest), true
+		case f.NoOptDefVal != "":
+			split = append(split, name)
+		case rest != "" && strict:
+			return append(split, name, rest), true
+		case rest != "":
+			return append(split, name, strings.TrimPrefix(rest, "=")), true
+		default:
+			return append(split, name), true
+		}
+	}
+	return split, true
+}
+
+// protectNegativeNumbers replaces arguments that are negative numbers (e.g. "-5" or "-0.5") with placeholders, unless
+// they are flag values or there is a shorthand flag matching the first digit. This allows pflag to parse them as
+// positional arguments instead of unknown shorthand flags. The returned function restores the original values in the
+// positional arguments of fs after it has been parsed.
+func protectNegativeNumbers(fs *pflag.FlagSet, args []string) ([]string, func()) {
+	var (
+		protected = make([]string, len(args))
+		originals = make(map[string]string)
+	)
+	copy(protected, args)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+			continue
+		}
+		if isNegativeNumber(arg) && fs.ShorthandLookup(arg[1:2]) == nil {
+			placeholder := fmt.Sprintf("\x00negative-number-%d", i)
+			protected[i], originals[placeholder] = placeholder, arg
+			continue
+		}
+		if n := flagArgs(fs, args[i:]); n > 1 {
+			i += n - 1
+		}
+	}
+
+	return protected, func() {
+		if len(originals) == 0 {
+			return
+		}
+		positional := fs.Args()
+		for i, arg := range positional {
+			if original, ok := originals[arg]; ok {
+				positional[i] = original
+			}
+		}
+	}
+}
+
+// isNegativeNumber returns true if the argument is a negative integer or decimal number.
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || !(arg[1] == '.' || (arg[1] >= '0' && arg[1] <= '9')) {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// expandResponseFiles replaces "@file" arguments with the lines of the file, where each (non-empty) line is one
+// argument. Lines starting with "#" are ignored, and "@@" can be used to pass a literal argument starting with "@".
+// Arguments after the "--" terminator are not expanded.
+func expandResponseFiles(args []string) ([]string, error) {
+	var expanded []string
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return append(expanded, args[i:]...), nil
+		case strings.HasPrefix(arg, "@@"):
+			expanded = append(expanded, arg[1:])
+		case strings.HasPrefix(arg, "@") && len(arg) > 1:
+			lines, err := readResponseFile(arg[1:])
+			if err != nil {
+				return nil, fmt.Errorf("reading response file %q: %w", arg[1:], err)
+			}
+			expanded = append(expanded, lines...)
+		default:
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, nil
+}
+
+// readResponseFile returns the arguments in the response file.
+func readResponseFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,136 @@
+// Package azurekeyvault provides a cli.FlagResolver for Azure Key Vault. Flags opt in to the resolver by setting the
+// Annotation to the name of a secret:
+//
+//	&cli.StringFlag{
+//		Name:        "token",
+//		Annotations: map[string]string{azurekeyvault.Annotation: "api-token"},
+//	}
+//
+// The secrets are read using the Key Vault REST API, so that the package does not depend on the Azure SDK.
+package azurekeyvault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Annotation is the key of the flag annotation that holds the name of the secret for the flag, optionally followed
+// by a version (e.g. "api-token/4d5f..."). The latest version is used if no version is given.
+const Annotation = "azure-keyvault-secret"
+
+// APIVersion is the version of the Key Vault REST API used by the Resolver.
+const APIVersion = "7.4"
+
+// Resolver implements cli.FlagResolver by reading secrets from Azure Key Vault. Flags that cannot be resolved because
+// of an error (e.g. missing permissions) are treated as unresolved, and the last error is available from Err.
+type Resolver struct {
+	// VaultURL is the URL of the key vault, e.g. "https://my-vault.vault.azure.net".
+	VaultURL string
+
+	// Token returns the OAuth2 access token (for the "https://vault.azure.net" resource) used to authenticate the
+	// requests, e.g. using azidentity.DefaultAzureCredential.
+	Token func(ctx context.Context) (string, error)
+
+	// HTTPClient (optional) used for the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout (optional) for reading a secret. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	err error
+}
+
+var _ cli.NamedResolver = &Resolver{}
+var _ cli.FlagSource = &Resolver{}
+
+// Resolve implements cli.FlagResolver.
+func (r *Resolver) Resolve(flag cli.Flag) (string, bool) {
+	name, ok := secretName(flag)
+	if !ok {
+		return "", false
+	}
+	v, err := r.read(name)
+	if err != nil {
+		r.err = fmt.Errorf("reading secret %q for flag %q: %w", name, flag.GetName(), err)
+		return "", false
+	}
+	return v, true
+}
+
+// Name implements cli.NamedResolver.
+func (r *Resolver) Name() string {
+	return "azure-key-vault"
+}
+
+// Source implements cli.FlagSource.
+func (r *Resolver) Source(flag cli.Flag) string {
+	name, _ := secretName(flag)
+	return "azure-keyvault " + strings.TrimSuffix(r.VaultURL, "/") + "/secrets/" + name
+}
+
+// Err returns the last error that occurred when resolving a flag.
+func (r *Resolver) Err() error {
+	return r.err
+}
+
+// secretName returns the name (and version) of the secret for the flag.
+func secretName(flag cli.Flag) (string, bool) {
+	af, ok := flag.(cli.AnnotatedFlag)
+	if !ok {
+		return "", false
+	}
+	name, ok := af.GetAnnotations()[Annotation]
+	return name, ok && name != ""
+}
+
+// read returns the value of the secret.
+func (r *Resolver) read(name string) (string, error) {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/secrets/%s?api-version=%s", strings.TrimSuffix(r.VaultURL, "/"), name, APIVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	if r.Token != nil {
+		token, err := r.Token(ctx)
+		if err != nil {
+			return "", fmt.Errorf("getting access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	return body.Value, nil
+}
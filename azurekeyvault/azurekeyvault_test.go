@@ -0,0 +1,71 @@
+package azurekeyvault_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/azurekeyvault"
+)
+
+func TestResolver(t *testing.T) {
+	secrets := map[string]string{
+		"/secrets/api-token":          "s3cr3t",
+		"/secrets/db-password/abc123": "hunter2",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != azurekeyvault.APIVersion {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		v, ok := secrets[r.URL.Path]
+		if !ok {
+			http.Error(w, `{"error": {"code": "SecretNotFound"}}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"value": %q, "id": %q}`, v, r.URL.Path)
+	}))
+	defer server.Close()
+
+	resolver := &azurekeyvault.Resolver{
+		VaultURL: server.URL,
+		Token:    func(context.Context) (string, error) { return "token", nil },
+	}
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "token", Annotations: map[string]string{azurekeyvault.Annotation: "api-token"}},
+			&cli.StringFlag{Name: "password", Annotations: map[string]string{azurekeyvault.Annotation: "db-password/abc123"}},
+			&cli.StringFlag{Name: "missing", Annotations: map[string]string{azurekeyvault.Annotation: "missing"}},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{resolver}},
+		Exec: func(c *cli.Context) error {
+			var got []string
+			for _, name := range []string{"token", "password", "missing"} {
+				v, err := c.GetString(name)
+				if err != nil {
+					return err
+				}
+				got = append(got, v)
+			}
+			if expected := []string{"s3cr3t", "hunter2", ""}; !reflect.DeepEqual(expected, got) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+			if source := c.FlagSource("token"); source != "azure-keyvault "+server.URL+"/secrets/api-token" {
+				t.Errorf("unexpected source: %s", source)
+			}
+			return nil
+		},
+	}
+	if err := c.Execute(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedErr := `reading secret "missing" for flag "missing": unexpected status 404 Not Found: {"error": {"code": "SecretNotFound"}}`
+	if err := resolver.Err(); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
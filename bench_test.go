@@ -0,0 +1,66 @@
+package cli_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+// newDeepCommand returns a command tree with the given depth, where each command has the given number of flags.
+func newDeepCommand(depth, flags int) (*cli.Command, []string) {
+	var (
+		root *cli.Command
+		prev *cli.Command
+		args []string
+	)
+	for i := 0; i < depth; i++ {
+		c := &cli.Command{Usage: fmt.Sprintf("level%d [flags]", i)}
+		if prev != nil {
+			prev.Subcommands = []*cli.Command{c}
+			args = append(args, fmt.Sprintf("level%d", i))
+		}
+		for j := 0; j < flags; j++ {
+			name := fmt.Sprintf("flag-%d-%d", i, j)
+			c.Flags = append(c.Flags, &cli.StringFlag{Name: name, Usage: "A flag"})
+			if j%10 == 0 {
+				args = append(args, "--"+name, "value")
+			}
+		}
+		if prev == nil {
+			root = c
+			root.Opts = cli.Options{Writer: io.Discard, ErrWriter: io.Discard}
+		}
+		prev = c
+	}
+	prev.Exec = func(c *cli.Context) error { return nil }
+	return root, args
+}
+
+func BenchmarkExecute(b *testing.B) {
+	for _, depth := range []int{1, 5} {
+		b.Run(fmt.Sprintf("depth=%d/flags=50", depth), func(b *testing.B) {
+			root, args := newDeepCommand(depth, 50)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := root.Execute(args); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkHelp(b *testing.B) {
+	root, args := newDeepCommand(5, 50)
+	args = append(args, "--help")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := root.Execute(args); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
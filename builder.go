@@ -0,0 +1,107 @@
+package cli
+
+import "fmt"
+
+// Builder is an alternative to defining commands as (deeply nested) struct literals. Errors are detected while
+// building the command, and the first error is returned by Build:
+//
+//	cmd, err := cli.New("printer [command]").
+//		Flag(&cli.BoolFlag{Name: "debug, d"}).
+//		Sub(
+//			cli.New("echo <text>").Help("Print the text").Exec(echo),
+//		).
+//		Build()
+type Builder struct {
+	cmd *Command
+	err error
+}
+
+// New returns a Builder for a command with the given usage.
+func New(usage string) *Builder {
+	b := &Builder{cmd: &Command{Usage: usage}}
+	b.check(b.cmd.validateUsage())
+	return b
+}
+
+// Help sets the help text of the command.
+func (b *Builder) Help(help string) *Builder {
+	b.cmd.Help = help
+	return b
+}
+
+// Examples sets the examples of the command.
+func (b *Builder) Examples(examples string) *Builder {
+	b.cmd.Examples = examples
+	return b
+}
+
+// Flag adds flags to the command.
+func (b *Builder) Flag(flags ...Flag) *Builder {
+	b.cmd.Flags = append(b.cmd.Flags, flags...)
+	b.check(b.cmd.validateFlags())
+	return b
+}
+
+// FlagGroup adds flag groups to the command.
+func (b *Builder) FlagGroup(groups ...FlagGroup) *Builder {
+	b.cmd.FlagGroups = append(b.cmd.FlagGroups, groups...)
+	return b
+}
+
+// Exec sets the function that executes the command.
+func (b *Builder) Exec(exec func(*Context) error) *Builder {
+	b.cmd.Exec = exec
+	if len(b.cmd.Subcommands) > 0 {
+		b.check(&ErrMisconfigured{cmd: b.cmd, msg: "cannot define both exec and subcommands"})
+	}
+	return b
+}
+
+// Sub adds subcommands to the command.
+func (b *Builder) Sub(subcommands ...*Builder) *Builder {
+	for _, s := range subcommands {
+		b.check(s.err)
+		for _, existing := range b.cmd.Subcommands {
+			if existing.name() == s.cmd.name() {
+				b.check(&ErrMisconfigured{cmd: b.cmd, msg: fmt.Sprintf("subcommand %q is defined more than once", s.cmd.name())})
+			}
+		}
+		b.cmd.Subcommands = append(b.cmd.Subcommands, s.cmd)
+	}
+	if b.cmd.Exec != nil {
+		b.check(&ErrMisconfigured{cmd: b.cmd, msg: "cannot define both exec and subcommands"})
+	}
+	return b
+}
+
+// Options sets the options of the command, which is only allowed for the root command.
+func (b *Builder) Options(opts Options) *Builder {
+	b.cmd.Opts = opts
+	return b
+}
+
+// Configure calls fn with the command being built, to set fields that do not have a method on the Builder.
+func (b *Builder) Configure(fn func(*Command)) *Builder {
+	fn(b.cmd)
+	return b
+}
+
+// Build returns the command, or the first error that was detected while building it. The entire command tree is
+// validated, so errors that can only be detected in the tree (e.g. flags that are redefined by a subcommand) are
+// returned by Build.
+func (b *Builder) Build() (*Command, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := b.cmd.initializeTree(); err != nil {
+		return nil, err
+	}
+	return b.cmd, nil
+}
+
+// check records the first error.
+func (b *Builder) check(err error) {
+	if b.err == nil && err != nil {
+		b.err = err
+	}
+}
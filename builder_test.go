@@ -0,0 +1,68 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestBuilder(t *testing.T) {
+	var b bytes.Buffer
+	echo := func(c *cli.Context) error {
+		return c.Print(strings.Join(c.Args(), " "))
+	}
+	cmd, err := cli.New("printer [command]").
+		Help("Print things").
+		Flag(&cli.BoolFlag{Name: "debug, d"}).
+		Options(cli.Options{Writer: &b}).
+		Sub(
+			cli.New("echo <text>").Help("Print the text").Exec(echo),
+		).
+		Build()
+	eq(t, nil, err)
+	eq(t, nil, cmd.Execute([]string{"echo", "hello", "world"}))
+	eq(t, "hello world\n", b.String())
+
+	tests := []struct {
+		description string
+		builder     *cli.Builder
+		expectedErr string
+	}{
+		{
+			description: "invalid usage",
+			builder:     cli.New("-printer"),
+			expectedErr: `misconfigured command "-printer": usage must start with a valid command name: "-printer"`,
+		},
+		{
+			description: "duplicate flags",
+			builder:     cli.New("printer").Flag(&cli.BoolFlag{Name: "debug"}, &cli.StringFlag{Name: "debug"}),
+			expectedErr: `misconfigured command "printer": flag "debug" is already defined (locally)`,
+		},
+		{
+			description: "exec and subcommands",
+			builder:     cli.New("printer").Exec(echo).Sub(cli.New("echo").Exec(echo)),
+			expectedErr: `misconfigured command "printer": cannot define both exec and subcommands`,
+		},
+		{
+			description: "error in subcommand",
+			builder:     cli.New("printer").Sub(cli.New("echo").Flag(&cli.BoolFlag{Name: "a, x"}, &cli.BoolFlag{Name: "b, x"})),
+			expectedErr: `misconfigured command "echo": shorthand "x" for flag "b" is already used (by flag "a")`,
+		},
+		{
+			description: "redefined flag in tree",
+			builder:     cli.New("printer").Flag(&cli.BoolFlag{Name: "debug"}).Sub(cli.New("echo").Flag(&cli.BoolFlag{Name: "debug"}).Exec(echo)),
+			expectedErr: `misconfigured command "printer echo": flag "debug" is already defined (inherited from "printer")`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			eq(t, tc.expectedErr, err.Error())
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import "strings"
+
+// chain is the state shared by the commands that are executed by a single invocation of the application (see
+// Options.ChainDelimiter).
+type chain struct {
+	values map[string]interface{}
+	result interface{}
+}
+
+// splitChain splits the arguments on the delimiter (see Options.ChainDelimiter), skipping empty parts. Values of
+// flags (e.g. "--suffix +") and the arguments after the "--" terminator are never split, unless the delimiter is "--".
+// The arguments are returned as a single part if there is no delimiter, or if all parts are empty.
+func (c *Command) splitChain(args []string) [][]string {
+	delimiter := c.options().ChainDelimiter
+	if delimiter == "" {
+		return [][]string{args}
+	}
+	var (
+		fs    = c.treeFlagSet()
+		parts [][]string
+		start int
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == delimiter:
+			if i > start {
+				parts = append(parts, args[start:i])
+			}
+			start = i + 1
+		case arg == "--":
+			i = len(args)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if n := flagArgs(fs, args[i:]); n > 1 {
+				i++
+			}
+		}
+	}
+	if start < len(args) {
+		parts = append(parts, args[start:])
+	}
+	if len(parts) == 0 {
+		return [][]string{nil}
+	}
+	return parts
+}
+
+// Shared returns the values that are shared by the chained commands (see Options.ChainDelimiter), e.g. so that a
+// "build" command can store the path of an artifact for a later "deploy" command. The values are only shared for a
+// single invocation of Execute, and the map can be modified directly.
+func (c *Context) Shared() map[string]interface{} {
+	if c.chain == nil {
+		c.chain = &chain{values: make(map[string]interface{})}
+	}
+	return c.chain.values
+}
+
+// PreviousResult returns the result (see SetResult) of the previous command in the chain (see
+// Options.ChainDelimiter), or nil if the command is the first in the chain.
+func (c *Context) PreviousResult() interface{} {
+	if c.chain == nil {
+		return nil
+	}
+	return c.chain.result
+}
@@ -0,0 +1,119 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestChainDelimiter(t *testing.T) {
+	tests := []struct {
+		description    string
+		args           []string
+		expectedSteps  []string
+		expectedResult interface{}
+		expectedErr    string
+	}{
+		{
+			description:    "chained commands",
+			args:           []string{"build", "--target", "linux", "+", "test", "+", "deploy"},
+			expectedSteps:  []string{"build linux", "test app-linux", "deploy app-linux (tested)"},
+			expectedResult: "deployed",
+		},
+		{
+			description:    "empty parts",
+			args:           []string{"+", "build", "+", "+"},
+			expectedSteps:  []string{"build amd64"},
+			expectedResult: "app-amd64",
+		},
+		{
+			description:   "stops on first error",
+			args:          []string{"build", "+", "fail", "+", "deploy"},
+			expectedSteps: []string{"build amd64", "fail"},
+			expectedErr:   "failed",
+		},
+		{
+			description:   "flag value equal to the delimiter",
+			args:          []string{"build", "--target", "+", "+", "test"},
+			expectedSteps: []string{"build +", "test app-+"},
+		},
+		{
+			description:   "arguments after terminator",
+			args:          []string{"echo", "--", "a", "+", "test"},
+			expectedSteps: []string{"echo a + test"},
+		},
+		{
+			description: "parse error",
+			args:        []string{"build", "--bogus", "+", "deploy"},
+			expectedErr: "parsing command: unknown flag: --bogus",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var steps []string
+			c := cli.Command{
+				Usage: "make [command]",
+				Opts:  cli.Options{ChainDelimiter: "+"},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "build [flags]",
+						Flags: []cli.Flag{&cli.StringFlag{Name: "target", Value: "amd64"}},
+						Exec: func(c *cli.Context) error {
+							target, _ := c.GetString("target")
+							steps = append(steps, "build "+target)
+							c.Shared()["artifact"] = "app-" + target
+							c.SetResult("app-" + target)
+							return nil
+						},
+					},
+					{
+						Usage: "test",
+						Exec: func(c *cli.Context) error {
+							steps = append(steps, fmt.Sprintf("test %v", c.PreviousResult()))
+							c.Shared()["tested"] = true
+							return nil
+						},
+					},
+					{
+						Usage: "deploy",
+						Exec: func(c *cli.Context) error {
+							step := fmt.Sprintf("deploy %v", c.Shared()["artifact"])
+							if tested, _ := c.Shared()["tested"].(bool); tested {
+								step += " (tested)"
+							}
+							steps = append(steps, step)
+							c.SetResult("deployed")
+							return nil
+						},
+					},
+					{
+						Usage: "echo [args...]",
+						Exec: func(c *cli.Context) error {
+							steps = append(steps, "echo "+strings.Join(c.Args(), " "))
+							return nil
+						},
+					},
+					{
+						Usage: "fail",
+						Exec: func(c *cli.Context) error {
+							steps = append(steps, "fail")
+							return errors.New("failed")
+						},
+					},
+				},
+			}
+			result, err := c.ExecuteResult(tc.args)
+			if tc.expectedErr != "" {
+				eq(t, tc.expectedErr, fmt.Sprint(err))
+			} else {
+				eq(t, nil, err)
+			}
+			eq(t, tc.expectedSteps, steps)
+			eq(t, tc.expectedResult, result)
+		})
+	}
+}
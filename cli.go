@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -20,12 +24,69 @@ type ErrMisconfigured struct {
 
 // Error implements errors.Error.
 func (e *ErrMisconfigured) Error() string {
-	return fmt.Sprintf("misconfigured command %q: %s", e.cmd.name(), e.msg)
+	return fmt.Sprintf("misconfigured command %q: %s", e.cmd.path(), e.msg)
 }
 
-// Context ...
-type Context struct {
-	*pflag.FlagSet
+// ErrUnknownFlag is returned when the arguments contain a flag that is not defined for the command.
+type ErrUnknownFlag struct {
+	// Flag as it was given in the arguments, e.g. "--name" or "-n".
+	Flag string
+
+	tr func(string) string
+}
+
+// Error implements errors.Error.
+func (e *ErrUnknownFlag) Error() string {
+	return fmt.Sprintf(translate(e.tr, "unknown flag: %s"), e.Flag)
+}
+
+// ErrAmbiguousFlag is returned when an abbreviated long flag matches more than one flag (see
+// Options.AllowFlagAbbreviations).
+type ErrAmbiguousFlag struct {
+	// Flag as it was given in the arguments, e.g. "--verb".
+	Flag string
+	// Candidates are the (sorted) flags that the abbreviation matches, e.g. "--verbose" and "--version".
+	Candidates []string
+
+	tr func(string) string
+}
+
+// Error implements errors.Error.
+func (e *ErrAmbiguousFlag) Error() string {
+	return fmt.Sprintf(translate(e.tr, "ambiguous flag: %s (matches %s)"), e.Flag, strings.Join(e.Candidates, ", "))
+}
+
+// ErrMissingFlagValue is returned when a flag that requires a value is given without one.
+type ErrMissingFlagValue struct {
+	// Flag as it was given in the arguments, e.g. "--name" or "-n".
+	Flag string
+
+	tr func(string) string
+}
+
+// Error implements errors.Error.
+func (e *ErrMissingFlagValue) Error() string {
+	return fmt.Sprintf(translate(e.tr, "flag needs an argument: %s"), e.Flag)
+}
+
+// ErrInvalidFlagValue is returned when the value given for a flag is not valid (e.g. "abc" for an IntFlag).
+type ErrInvalidFlagValue struct {
+	// Name of the flag, without dashes.
+	Name string
+	// Value that was given for the flag.
+	Value string
+	// Err is the error returned when setting the value.
+	Err error
+}
+
+// Error implements errors.Error.
+func (e *ErrInvalidFlagValue) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned when setting the value.
+func (e *ErrInvalidFlagValue) Unwrap() error {
+	return e.Err
 }
 
 // Options ...
@@ -35,6 +96,141 @@ type Options struct {
 	ErrWriter io.Writer
 	UsageFunc func(*Command) string
 	Resolvers []FlagResolver
+	Color     ColorMode
+
+	// VersionFunc produces the version string printed by --version and the version subcommand.
+	VersionFunc func(*Command) string
+
+	// Translator (optional) translates the user-facing strings of the framework, e.g. the section headings in
+	// usage texts.
+	Translator Translator
+
+	// UpdateChecker (optional) checks for newer versions of the application while commands are executing.
+	UpdateChecker *UpdateChecker
+
+	// Telemetry (optional) receives events for the commands that are executed.
+	Telemetry *Telemetry
+
+	// Now (optional) returns the current time for the framework, e.g. for the UpdateChecker cache and TelemetryEvents.
+	// Defaults to time.Now.
+	Now func() time.Time
+
+	// Rand (optional) is the source of randomness for the framework, e.g. for the jitter of Retry. Defaults to the
+	// global source of math/rand.
+	Rand *rand.Rand
+
+	// ResponseFiles enables expansion of "@file" arguments, where each line in the file is spliced into the
+	// arguments before they are parsed.
+	ResponseFiles bool
+
+	// EnvCommand adds a hidden "env" subcommand to the root command (if it has subcommands), which prints the
+	// effective value and source of each flag, e.g. "mycli env deploy --dry-run".
+	EnvCommand bool
+
+	// ConfigCommand adds a "config" subcommand to the root command (if it has subcommands), which edits the default
+	// flag values in the ConfigFile (see SetConfigValue).
+	ConfigCommand bool
+
+	// CompletionCommand adds a "completion" subcommand to the root command (if it has subcommands), which prints or
+	// installs the shell completion scripts (see GenCompletion).
+	CompletionCommand bool
+
+	// ConfigFile (optional) is the config file edited by the config command. Defaults to AppConfigPath for the name of
+	// the root command.
+	ConfigFile string
+
+	// FeatureGates enables (or disables) feature gates by name. Commands and flags (see GatedFlag) behind a gate
+	// that is not enabled are hidden, and return an ErrFeatureNotEnabled when they are used.
+	FeatureGates map[string]bool
+
+	// FeatureGatesEnvVar (optional) is an environment variable with a comma-separated list of feature gates to
+	// enable, e.g. MYCLI_FEATURES=preview,beta. Gates that are set in FeatureGates take precedence.
+	FeatureGatesEnvVar string
+
+	// TraceEnvVar (optional) is an environment variable that prints a trace of the parsing to the ErrWriter when set
+	// (to anything but "" or "0"), e.g. MYCLI_TRACE=1. Resolved values are never printed.
+	TraceEnvVar string
+
+	// ProfilingFlags adds the hidden --cpuprofile, --memprofile and --trace flags to the root command, which write
+	// the profiles (using runtime/pprof and runtime/trace) for the execution of the command to the given files.
+	ProfilingFlags bool
+
+	// VerbosityFlags adds the --verbose/-v and --quiet/-q flags to the root command, which decide the level
+	// of Context.Logger.
+	VerbosityFlags bool
+
+	// Aliases maps alias names to the arguments they expand to, e.g. "co" to "checkout --track". Only the first
+	// positional argument is expanded.
+	Aliases map[string]string
+
+	// ArgsPreprocessor (optional) rewrites the arguments before they are parsed (after expanding response files).
+	// Errors are returned as parse errors.
+	ArgsPreprocessor func(args []string) ([]string, error)
+
+	// ChainDelimiter (optional) splits the arguments into commands that are executed in order, e.g. with "+" in
+	// "mycli build + test". The commands can share values using Context.Shared and Context.PreviousResult.
+	ChainDelimiter string
+
+	// Migrations (optional) rewrite the invocations of commands that have been moved or renamed to their new path,
+	// and print a deprecation notice (see Migration).
+	Migrations []Migration
+
+	// AllowSlashFlags translates Windows-style flags that are defined in the command tree into long flags, e.g.
+	// "/region:eu-west-1" into "--region=eu-west-1" and "/?" into "--help".
+	AllowSlashFlags bool
+
+	// StrictPOSIX enforces the POSIX utility syntax guidelines for every command, e.g. flags must be given before the
+	// operands and shorthand values are used verbatim. Parse errors are prefixed with the path of the command.
+	StrictPOSIX bool
+
+	// AllowFlagAbbreviations allows long flags to be abbreviated to any unambiguous prefix, e.g. "--verb" for
+	// "--verbose" (an ErrAmbiguousFlag is returned otherwise). Ignored when StrictPOSIX is set.
+	AllowFlagAbbreviations bool
+
+	// Environ (optional) replaces the environment of the process (os.Environ) for the framework, e.g. so that parallel
+	// tests do not need os.Setenv. An EnvVarResolver wrapped by another resolver must be given it using LookupEnv.
+	Environ []string
+
+	// EnvPrefix (optional) is the prefix of the environment variables that belong to the application, e.g. "MYCLI_".
+	// Variables with the prefix that are not used by any flag are handled according to UnknownEnvVars.
+	EnvPrefix string
+
+	// EnvVarDecorator (optional) formats the names of environment variables in usage texts and flag sources. Defaults
+	// to "$NAME", or "%NAME%" on Windows.
+	EnvVarDecorator func(name string) string
+
+	// UnknownEnvVars decides what happens when there are unknown environment variables with the EnvPrefix. Defaults
+	// to UnknownEnvIgnore.
+	UnknownEnvVars UnknownEnvPolicy
+
+	// WarnEnvVarConflicts prints a warning when a flag is resolved from an environment variable, and other
+	// environment variables of the flag are set to different values.
+	WarnEnvVarConflicts bool
+
+	// PrependResolvers and AppendResolvers are applied before and after the Resolvers, so that resolvers can be
+	// added without replacing the default EnvVarResolver.
+	PrependResolvers []FlagResolver
+	AppendResolvers  []FlagResolver
+
+	// ExpandResolvedValues expands ${name} references to other flags (or environment variables) in the values
+	// returned by resolvers, e.g. "https://${region}.api.example.com".
+	ExpandResolvedValues bool
+
+	// Pager pipes help output through a pager (like git does) when it is taller than the terminal, and the ErrWriter
+	// is a terminal.
+	Pager bool
+
+	// PagerCommand (optional) is the pager used when Pager is enabled. Defaults to $PAGER, or "less -FRX" if it is
+	// not set.
+	PagerCommand string
+
+	// HelpFlag (optional) is the name (and shorthand) of the flag that prints the usage of a command, e.g. "help" for
+	// a long-only flag. Defaults to "help, h".
+	HelpFlag string
+
+	// DisableDefaultInUsage hides the default values of all flags in usage texts and docs (see also
+	// DefaultTextFlag).
+	DisableDefaultInUsage bool
 }
 
 // complete passes default values to the options that are unset.
@@ -51,28 +247,125 @@ func (opts *Options) complete() {
 	if opts.UsageFunc == nil {
 		opts.UsageFunc = defaultUsageFunc
 	}
+	if opts.VersionFunc == nil {
+		opts.VersionFunc = defaultVersionFunc
+	}
 	if opts.Resolvers == nil {
 		opts.Resolvers = []FlagResolver{&EnvVarResolver{}}
 	}
 }
 
+// resolvers returns the resolvers in order of precedence (see PrependResolvers and AppendResolvers).
+func (opts *Options) resolvers() []FlagResolver {
+	if len(opts.PrependResolvers) == 0 && len(opts.AppendResolvers) == 0 {
+		return opts.Resolvers
+	}
+	resolvers := make([]FlagResolver, 0, len(opts.PrependResolvers)+len(opts.Resolvers)+len(opts.AppendResolvers))
+	resolvers = append(resolvers, opts.PrependResolvers...)
+	resolvers = append(resolvers, opts.Resolvers...)
+	return append(resolvers, opts.AppendResolvers...)
+}
+
+// resolvers returns the resolvers of the options, where each PathResolver is bound to the path of the command, and
+// the EnvVarResolver and CachingResolvers to the environment and clock of the options (unless they have their own).
+// The EnvVarResolver also uses the EnvVarDecorator of the options for its sources.
+func (c *Command) resolvers() []FlagResolver {
+	var (
+		resolvers = c.options().resolvers()
+		bound     = make([]FlagResolver, len(resolvers))
+	)
+	for i, r := range resolvers {
+		if er, ok := r.(*EnvVarResolver); ok {
+			cp := *er
+			if cp.LookupEnv == nil {
+				cp.LookupEnv = c.options().lookupEnv
+			}
+			cp.decorate = c.options().decorateEnvVar
+			r = &cp
+		}
+		if cr, ok := r.(*CachingResolver); ok && cr.Now == nil {
+			r = &optionsCachingResolver{CachingResolver: cr, now: c.options().now}
+		}
+		if pr, ok := r.(PathResolver); ok {
+			r = &pathResolver{resolver: pr, path: strings.Fields(c.path())}
+		}
+		bound[i] = r
+	}
+	return bound
+}
+
 // Command ...
 type Command struct {
 	Usage       string
 	Help        string
+	Examples    string
 	Flags       []Flag
+	FlagGroups  []FlagGroup
 	Exec        func(*Context) error
 	Subcommands []*Command
-	Opts        Options
 
-	fs     *pflag.FlagSet
-	parent *Command
+	// ArgsUsage (optional) is the synopsis of the arguments of the command, e.g. "[flags] <file>...". When set, the
+	// Usage must only contain the name of the command, and the usage line is composed from the name and ArgsUsage.
+	ArgsUsage string
+
+	// Opts configures the application and can only be set on the root command. Subcommands use the options of
+	// the root command.
+	Opts Options
+
+	// DisableInterspersed stops flag parsing at the first positional argument, so that e.g. "run prog --flag" passes
+	// "--flag" as an argument instead of parsing it.
+	DisableInterspersed bool
+
+	// SkipFlagParsing passes all arguments after the command name to Exec untouched, which is useful for commands
+	// that wrap other programs (e.g. "exec" or "run").
+	SkipFlagParsing bool
+
+	// DisableHelpFlag disables the help flag (see Options.HelpFlag) for the command, so that e.g. -h can be used as
+	// the shorthand of a --host flag. Note that flags defined by the command always take precedence over the help
+	// flag.
+	DisableHelpFlag bool
+
+	// ValidArgs (optional) are the accepted values for the first positional argument of the command, e.g. the names
+	// of the resources that "get <resource>" can list. Other values are rejected when parsing the command, and the
+	// values are included in the Spec (e.g. for shell completion).
+	ValidArgs []string
+
+	// Annotations are arbitrary metadata for the command, which can be used by extensions (e.g. doc generators or
+	// policy tooling). They are not used by this package, except for including them in the Spec.
+	Annotations map[string]string
+
+	// Hidden commands can be executed, but are not listed in usage texts, docs or specs.
+	Hidden bool
+
+	// SingleInstance commands fail with an ErrAlreadyRunning when another instance of the command is running,
+	// which is detected using a lock file.
+	SingleInstance bool
+
+	// LockFile (optional) is the path of the lock file for SingleInstance. Defaults to a file in the temporary
+	// directory named after the command path, e.g. "mycli-deploy.lock".
+	LockFile string
+
+	// FeatureGate (optional) is the name of the feature gate for the command (see Options.FeatureGates). Commands
+	// behind a disabled gate are hidden, and return an ErrFeatureNotEnabled when executed.
+	FeatureGate string
+
+	// Version of the application. When set on the root command, a --version flag and a version subcommand (if
+	// the root command has subcommands) are added.
+	Version string
+
+	fs       *pflag.FlagSet
+	sources  map[string]string
+	parent   *Command
+	builtins []*Command
+	groups   [][]Flag
+	required map[string]bool
+	hidden   map[string]bool
 }
 
 // initialize ...
 func (c *Command) initialize() (err error) {
-	if c.Usage == "" {
-		return &ErrMisconfigured{cmd: c, msg: "usage must be defined"}
+	if err := c.validateUsage(); err != nil {
+		return err
 	}
 	if c.Exec == nil && len(c.Subcommands) == 0 {
 		return &ErrMisconfigured{cmd: c, msg: "must define either exec or subcommands"}
@@ -80,127 +373,592 @@ func (c *Command) initialize() (err error) {
 	if c.Exec != nil && len(c.Subcommands) > 0 {
 		return &ErrMisconfigured{cmd: c, msg: "cannot define both exec and subcommands"}
 	}
-	// TODO: Ensure that options can only be set on the root command.
-	c.Opts.complete()
+	if len(c.ValidArgs) > 0 && len(c.Subcommands) > 0 {
+		return &ErrMisconfigured{cmd: c, msg: "cannot define both valid args and subcommands"}
+	}
+	if c.SkipFlagParsing && len(c.Subcommands) > 0 {
+		return &ErrMisconfigured{cmd: c, msg: "cannot skip flag parsing for a command with subcommands"}
+	}
+	if c.parent != nil && !reflect.ValueOf(c.Opts).IsZero() {
+		return &ErrMisconfigured{cmd: c, msg: "options can only be set on the root command"}
+	}
+	if c.parent == nil {
+		c.Opts.complete()
+	}
 
+	// Rebuild all state from any previous invocation, so that the same command can be executed repeatedly.
+	c.builtins, c.groups, c.sources = nil, nil, nil
+	if c.parent == nil {
+		c.builtins = c.builtinCommands()
+	}
+	for _, group := range c.FlagGroups {
+		c.groups = append(c.groups, group.Flags)
+	}
+
+	if err := c.validateFlags(); err != nil {
+		return err
+	}
+
+	// The flags are only sorted when rendering usage (see flagSet), since pflag sorts them every time the flag set is
+	// visited otherwise (e.g. when it is added to the flag set of a subcommand).
 	c.fs = newFS(c.LocalFlags())
+	c.fs.SortFlags = false
 	if c.parent != nil {
 		c.fs.AddFlagSet(c.parent.fs)
 	}
+	c.fs.SetInterspersed(c.interspersed())
+	for name, hidden := range c.hidden {
+		if pf := c.fs.Lookup(name); pf != nil {
+			pf.Hidden = hidden
+		}
+	}
+	for _, f := range c.LocalFlags() {
+		if !c.flagEnabled(f) {
+			c.fs.MarkHidden(f.GetName())
+		}
+	}
 
-	for _, subcommand := range c.Subcommands {
+	names := make(map[string]bool)
+	for _, subcommand := range c.subcommands() {
 		if err := subcommand.setParent(c); err != nil {
 			return err
 		}
+		if err := subcommand.validateUsage(); err != nil {
+			return err
+		}
+		if name := subcommand.name(); names[name] {
+			return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("subcommand %q is defined more than once", name)}
+		}
+		names[subcommand.name()] = true
+	}
+	if c.parent == nil {
+		for alias := range c.Opts.Aliases {
+			if names[alias] {
+				return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("alias %q conflicts with a subcommand", alias)}
+			}
+		}
+	}
+	return nil
+}
+
+// validateUsage returns an error if the usage is not defined or does not start with a valid command name.
+func (c *Command) validateUsage() error {
+	if c.Usage == "" {
+		return &ErrMisconfigured{cmd: c, msg: "usage must be defined"}
+	}
+	if name := c.name(); name == "" || strings.HasPrefix(name, "-") {
+		return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("usage must start with a valid command name: %q", c.Usage)}
+	}
+	if c.ArgsUsage == "" {
+		return nil
+	}
+	if strings.TrimSpace(c.Usage) != c.name() {
+		return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("usage cannot contain arguments when args usage is defined: %q", c.Usage)}
+	}
+	if err := validateArgsUsage(c.ArgsUsage); err != nil {
+		return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("invalid args usage %q: %s", c.ArgsUsage, err)}
+	}
+	return nil
+}
+
+// validateArgsUsage returns an error if the brackets in the synopsis of the arguments are not balanced, e.g.
+// "[flags] <file".
+func validateArgsUsage(s string) error {
+	pairs := map[rune]rune{']': '[', '>': '<', ')': '(', '}': '{'}
+	var open []rune
+	for _, r := range s {
+		switch r {
+		case '[', '<', '(', '{':
+			open = append(open, r)
+		case ']', '>', ')', '}':
+			if len(open) == 0 || open[len(open)-1] != pairs[r] {
+				return fmt.Errorf("unexpected %q", r)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed %q", open[len(open)-1])
+	}
+	return nil
+}
+
+// validateFlags returns an error if a flag name or shorthand is used more than once by the local flags, or if a
+// local flag redefines a flag inherited from a parent command.
+func (c *Command) validateFlags() error {
+	type owner struct {
+		cmd  *Command
+		flag string
+	}
+	var (
+		names       = make(map[string]*Command)
+		shorthands  = make(map[string]owner)
+		conditional []Flag
+	)
+	for p := c.parent; p != nil; p = p.parent {
+		for _, f := range p.LocalFlags() {
+			names[f.GetName()] = p
+			if s := f.GetShorthand(); s != "" {
+				shorthands[s] = owner{cmd: p, flag: f.GetName()}
+			}
+		}
+	}
+	for _, f := range c.LocalFlags() {
+		name, shorthand := f.GetName(), f.GetShorthand()
+		if name == "" {
+			return &ErrMisconfigured{cmd: c, msg: "flag name must be defined"}
+		}
+		if cond, _, _ := requiredIf(f); cond != "" {
+			conditional = append(conditional, f)
+		}
+		if v, ok := f.(*VarFlag); ok {
+			if err := v.validate(); err != nil {
+				return &ErrMisconfigured{cmd: c, msg: err.Error()}
+			}
+		}
+		if p, ok := names[name]; ok {
+			location := "locally"
+			if p != c {
+				location = fmt.Sprintf("inherited from %q", p.path())
+			}
+			return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("flag %q is already defined (%s)", name, location)}
+		}
+		names[name] = c
+		if shorthand == "" {
+			continue
+		}
+		if len(shorthand) > 1 {
+			return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("shorthand %q for flag %q must be a single character", shorthand, name)}
+		}
+		if o, ok := shorthands[shorthand]; ok {
+			by := fmt.Sprintf("flag %q", o.flag)
+			if o.cmd != c {
+				by += fmt.Sprintf(" inherited from %q", o.cmd.path())
+			}
+			return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("shorthand %q for flag %q is already used (by %s)", shorthand, name, by)}
+		}
+		shorthands[shorthand] = owner{cmd: c, flag: name}
+	}
+	for _, f := range conditional {
+		if name, _, _ := requiredIf(f); names[name] == nil {
+			return &ErrMisconfigured{cmd: c, msg: fmt.Sprintf("condition for flag %q refers to undefined flag %q", f.GetName(), name)}
+		}
 	}
 	return nil
 }
 
 func (c *Command) LocalFlags() []Flag {
+	flags := c.ownFlags()
+	for _, group := range c.groups {
+		flags = append(flags, group...)
+	}
+	return flags
+}
+
+// ownFlags returns the local flags that are not part of a FlagGroup.
+func (c *Command) ownFlags() []Flag {
+	if c.parent == nil {
+		return append(c.Flags[:len(c.Flags):len(c.Flags)], c.builtinFlags()...)
+	}
 	return c.Flags
 }
 
 func (c *Command) GlobalFlags() []Flag {
-	var fs []Flag
-	if c.parent != nil {
-		fs = append(fs, c.parent.CombinedFlags()...)
+	if c.parent == nil {
+		return nil
 	}
-	return fs
+	return c.parent.CombinedFlags()
 }
 
 func (c *Command) CombinedFlags() []Flag {
-	fs := c.LocalFlags()
-	if c.parent != nil {
-		fs = append(fs, c.parent.CombinedFlags()...)
+	var fs []Flag
+	for p := c; p != nil; p = p.parent {
+		fs = append(fs, p.LocalFlags()...)
 	}
 	return fs
 }
 
-// parse ...
-func (c *Command) parse(args []string) (*Command, error) {
+// MarkRequired changes whether a flag defined by the command (in Flags or FlagGroups) is required, regardless of
+// the Required field of the flag. The change applies to subsequent invocations of the command, and is reflected in
+// docs and specs.
+func (c *Command) MarkRequired(name string, required bool) error {
+	if !c.definesFlag(name) {
+		return fmt.Errorf("flag %q is not defined by command %q", name, c.name())
+	}
+	if c.required == nil {
+		c.required = make(map[string]bool)
+	}
+	c.required[name] = required
+	return nil
+}
+
+// MarkHidden changes whether a flag defined by the command (in Flags or FlagGroups) is hidden from usage texts, docs
+// and specs. The change applies to subsequent invocations of the command.
+func (c *Command) MarkHidden(name string, hidden bool) error {
+	if !c.definesFlag(name) {
+		return fmt.Errorf("flag %q is not defined by command %q", name, c.name())
+	}
+	if c.hidden == nil {
+		c.hidden = make(map[string]bool)
+	}
+	c.hidden[name] = hidden
+	return nil
+}
+
+// definesFlag returns true if the flag is defined in the Flags or FlagGroups of the command.
+func (c *Command) definesFlag(name string) bool {
+	flags := c.Flags
+	for _, group := range c.FlagGroups {
+		flags = append(flags[:len(flags):len(flags)], group.Flags...)
+	}
+	for _, f := range flags {
+		if f.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired returns true if the flag is required, taking MarkRequired into account.
+func (c *Command) isRequired(f Flag) bool {
+	for p := c; p != nil; p = p.parent {
+		if required, ok := p.required[f.GetName()]; ok {
+			return required
+		}
+	}
+	return f.IsRequired()
+}
+
+// lookupFlag returns the parsed pflag.Flag for the first flag available to the command that matches the predicate.
+func (c *Command) lookupFlag(match func(Flag) bool) *pflag.Flag {
+	for _, f := range c.CombinedFlags() {
+		if match(f) {
+			return c.fs.Lookup(f.GetName())
+		}
+	}
+	return nil
+}
+
+// initializeTree initializes the command and all of its subcommands.
+func (c *Command) initializeTree() error {
 	if err := c.initialize(); err != nil {
-		return nil, err
+		return err
 	}
-	var (
-		parseError    error
-		unparsed      []string
-		helpRequested bool
-	)
-	if err := c.fs.Parse(args); err != nil {
-		switch {
-		case isUnknownFlagErr(err):
-			// Unknown flags might belong to a subcommand so we wait to return. We should remove arguments that have
-			// been successfully parsed, which can be done somewhat hackily by parsing the name of the flag from the
-			// error message.
-			if i := strings.Index(err.Error(), "-"); i > 0 {
-				failedArg := err.Error()[i:]
-				for ii, arg := range args {
-					if arg == failedArg {
-						unparsed = args[ii:]
-						break
-					}
-				}
-			}
-			parseError = err
-		case errors.Is(err, pflag.ErrHelp):
-			// Wait with returning error until we have checked arguments to see if --help was specified for a subcommand.
-			parseError, helpRequested = err, true
-		default:
-			return nil, err
+	for _, subcommand := range c.subcommands() {
+		if err := subcommand.initializeTree(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builtinFlags returns the flags that are added to the root command by Options.
+func (c *Command) builtinFlags() []Flag {
+	var fs []Flag
+	if c.options().VerbosityFlags {
+		fs = append(fs, verbosityFlags()...)
+	}
+	if c.Version != "" {
+		fs = append(fs, versionFlag())
+	}
+	if c.options().ProfilingFlags {
+		fs = append(fs, profilingFlags()...)
+	}
+	return fs
+}
+
+// builtinCommands returns the subcommands that are added to the root command.
+func (c *Command) builtinCommands() []*Command {
+	var cmds []*Command
+	if c.Version != "" && len(c.Subcommands) > 0 {
+		cmds = append(cmds, versionCommand())
+	}
+	if c.options().EnvCommand && len(c.Subcommands) > 0 {
+		cmds = append(cmds, envCommand())
+	}
+	if c.options().ConfigCommand && len(c.Subcommands) > 0 {
+		cmds = append(cmds, configCommand())
+	}
+	if c.options().CompletionCommand && len(c.Subcommands) > 0 {
+		cmds = append(cmds, completionCommand(), completeCommand())
+	}
+	return cmds
+}
+
+// subcommands returns the subcommands of the command, including builtin commands.
+func (c *Command) subcommands() []*Command {
+	return append(c.Subcommands[:len(c.Subcommands):len(c.Subcommands)], c.builtins...)
+}
+
+// visibleSubcommands returns the subcommands that are not hidden.
+func (c *Command) visibleSubcommands() []*Command {
+	var cmds []*Command
+	for _, s := range c.subcommands() {
+		if !s.Hidden && s.featureEnabled(s.FeatureGate) {
+			cmds = append(cmds, s)
 		}
 	}
+	return cmds
+}
 
-	if err := ResolveMissingFlags(c.fs, c.Flags, c.Opts.Resolvers...); err != nil {
+// parse resolves the command selected by the arguments and parses its flags. This is done in two phases: first the
+// subcommand path is resolved by scanning the arguments, and then the combined flags of the selected command (which
+// includes the flags of its parents) are parsed once.
+func (c *Command) parse(args []string) (*Command, error) {
+	cmd, args, offset, err := c.dispatch(args, 0)
+	if err != nil {
 		return nil, err
 	}
+	return cmd, cmd.parseFlags(args, offset)
+}
 
-	if len(c.Subcommands) > 0 {
-		for _, subcommand := range c.Subcommands {
-			if subcommand.name() == c.fs.Arg(0) {
-				args = append(c.fs.Args()[1:], unparsed...)
+// dispatch initializes the command and returns the subcommand selected by the arguments (or the command itself), along
+// with the arguments that remain after removing the subcommand names. The offset is the index of the first argument
+// given after the name of the selected command, i.e. the arguments before it are flags for the parent commands.
+func (c *Command) dispatch(args []string, offset int) (*Command, []string, int, error) {
+	if err := c.initialize(); err != nil {
+		return nil, nil, 0, err
+	}
+	if c.parent == nil {
+		c.tracef("arguments %q", args)
+	}
+	if c.parent == nil && len(c.Opts.Aliases) > 0 {
+		expanded, err := c.expandAlias(args)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		c.traceRewrite("expanded alias", args, expanded)
+		args = expanded
+	}
+	if c.parent == nil && len(c.Opts.Migrations) > 0 {
+		migrated := c.migrate(args)
+		c.traceRewrite("migrated", args, migrated)
+		args = migrated
+	}
+	if c.parent == nil && c.Opts.AllowSlashFlags {
+		translated := c.translateSlashFlags(args)
+		c.traceRewrite("translated slash flags", args, translated)
+		args = translated
+	}
+	if subcommand, i := c.splitArgs(args); subcommand != nil {
+		if err := subcommand.commandEnabled(); err != nil {
+			return nil, nil, 0, err
+		}
+		c.tracef("argument %d selects subcommand %q", i, subcommand.name())
+		return subcommand.dispatch(append(args[:i:i], args[i+1:]...), i)
+	}
+	c.tracef("selected for execution")
+	return c, args, offset, nil
+}
 
-				cmd, err := subcommand.parse(args)
-				if err != nil {
-					return cmd, err
-				}
-				if helpRequested {
-					return cmd, parseError
-				}
-				return cmd, nil
-			}
+// parseFlags parses the arguments for the selected command and resolves missing flags.
+func (c *Command) parseFlags(args []string, offset int) error {
+	if c.SkipFlagParsing {
+		args = append(append(args[:offset:offset], "--"), args[offset:]...)
+	}
+	expanded, err := c.expandAbbreviations(args)
+	if err != nil {
+		return err
+	}
+	c.traceRewrite("expanded abbreviations", args, expanded)
+	split := splitShorthands(c.fs, expanded, c.interspersed(), c.options().StrictPOSIX)
+	c.traceRewrite("split shorthands", expanded, split)
+	args = split
+	args, restore := protectNegativeNumbers(c.fs, args)
+	// Note that checkFlags returns pflag.ErrHelp for the help flag at any level of the command tree, which
+	// short-circuits the checks below so that e.g. "root nested --help" works for intermediate commands.
+	if err := checkFlags(c.fs, args, c.interspersed(), c.helpFlag(), c.tr); err != nil {
+		return err
+	}
+	err = c.fs.ParseAll(args, func(f *pflag.Flag, value string) error {
+		if v, ok := f.Value.(*repeatValue); ok {
+			v.tr = c.tr
 		}
-		if !helpRequested {
-			parseError = errors.New("no subcommand specified. See --help")
+		if err := c.fs.Set(f.Name, value); err != nil {
+			return &ErrInvalidFlagValue{Name: f.Name, Value: value, Err: err}
 		}
+		c.tracef("flag --%s bound to %q", f.Name, value)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	restore()
+	c.tracef("positional arguments %q", c.fs.Args())
 
-	return c, parseError
+	if c.root().versionRequested() {
+		return errVersion
+	}
+	if err := c.checkGatedFlags(); err != nil {
+		return err
+	}
+
+	// Required flags are only reported once the command that will be executed is known, and help (or the version)
+	// has not been requested. The flags are still resolved for commands with subcommands, so that the env command can
+	// show the effective values.
+	flags := c.enabledFlags(c.CombinedFlags())
+	var expandEnv func(string) (string, bool)
+	if c.options().ExpandResolvedValues {
+		expandEnv = c.options().lookupEnv
+	}
+	sources, missing, err := resolveMissingFlags(c.fs, flags, c.resolvers(), c.isRequired, expandEnv)
+	c.sources = sources
+	c.traceResolved(flags, sources)
+	if err != nil {
+		return err
+	}
+	if conditional := c.missingConditionalFlags(flags, sources); len(conditional) > 0 {
+		missing = append(missing, conditional...)
+		sort.Strings(missing)
+	}
+	if len(c.subcommands()) > 0 {
+		var names []string
+		for _, s := range c.visibleSubcommands() {
+			names = append(names, s.name())
+		}
+		return fmt.Errorf(c.tr("%s: no subcommand specified (available: %s). See --help"), c.path(), strings.Join(names, ", "))
+	}
+	if err := c.checkValidArgs(); err != nil {
+		return err
+	}
+	if err := c.checkEnvVars(); err != nil {
+		return err
+	}
+	c.warnEnvVarConflicts(flags, sources)
+	if len(missing) > 0 {
+		return fmt.Errorf(c.tr("missing required flags %v"), missing)
+	}
+	return nil
+}
+
+// ExecuteWithIO executes the command like Execute, using the given streams instead of the Reader, Writer and ErrWriter
+// of the Options (which are restored afterwards). This is useful when there are no standard streams, e.g. when the
+// application is compiled to WebAssembly (GOOS=js or wasip1) and runs in a browser-based playground or as a plugin.
+// Streams that are nil default to the standard streams, see Options.
+func (c *Command) ExecuteWithIO(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	opts := c.Opts
+	defer func() { c.Opts = opts }()
+
+	c.Opts.Reader, c.Opts.Writer, c.Opts.ErrWriter = stdin, stdout, stderr
+	return c.Execute(args)
+}
+
+// ExecuteResult executes the command like Execute, and returns the result set by the Exec function using
+// Context.SetResult (or nil if it was not set). The result is returned even if Exec returns an error. When commands are
+// chained (see Options.ChainDelimiter), the result of the last command that was executed is returned.
+func (c *Command) ExecuteResult(args []string) (interface{}, error) {
+	return c.execute(args)
 }
 
 // Execute ...
 func (c *Command) Execute(args []string) error {
+	_, err := c.execute(args)
+	return err
+}
+
+// execute implements Execute and ExecuteResult.
+func (c *Command) execute(args []string) (interface{}, error) {
+	rawArgs := append([]string(nil), args...)
+	if c.options().ResponseFiles {
+		expanded, err := expandResponseFiles(args)
+		if err != nil {
+			return nil, fmt.Errorf(c.tr("parsing command: %w"), err)
+		}
+		args = expanded
+	}
+	if preprocess := c.options().ArgsPreprocessor; preprocess != nil {
+		processed, err := preprocess(args)
+		if err != nil {
+			return nil, fmt.Errorf(c.tr("parsing command: %w"), err)
+		}
+		c.traceRewrite("preprocessed", args, processed)
+		args = processed
+	}
+	state := &chain{values: make(map[string]interface{})}
+	for _, segment := range c.splitChain(args) {
+		result, err := c.executeSegment(segment, rawArgs, state)
+		if err != nil {
+			return result, err
+		}
+		state.result = result
+	}
+	return state.result, nil
+}
+
+// executeSegment parses and executes a single command (see Options.ChainDelimiter).
+func (c *Command) executeSegment(args, rawArgs []string, state *chain) (interface{}, error) {
 	cmd, err := c.parse(args)
 	if err != nil {
 		if errors.Is(err, pflag.ErrHelp) {
-			fmt.Fprintln(cmd.Opts.ErrWriter, cmd.Opts.UsageFunc(cmd))
-			return nil
+			cmd.printHelp()
+			return nil, nil
+		}
+		if errors.Is(err, errVersion) {
+			return nil, cmd.printVersion(cmd.options().Writer)
+		}
+		if c.options().StrictPOSIX {
+			if cmd == nil {
+				cmd = c
+			}
+			return nil, fmt.Errorf("%s: %w", cmd.path(), err)
 		}
-		return fmt.Errorf("parsing command: %w", err)
+		return nil, fmt.Errorf(c.tr("parsing command: %w"), err)
 	}
-	return cmd.Exec(&Context{cmd.fs})
+	printUpdateHint := cmd.options().UpdateChecker.start(cmd)
+	defer printUpdateHint()
+
+	unlock, err := cmd.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stopProfiling, err := cmd.startProfiling()
+	if err != nil {
+		return nil, err
+	}
+
+	finish := cmd.options().Telemetry.start(cmd)
+	ctx := &Context{FlagSet: cmd.fs, cmd: cmd, rawArgs: rawArgs, chain: state}
+	err = cmd.Exec(ctx)
+	finish(err)
+
+	if perr := stopProfiling(); perr != nil && err == nil {
+		err = perr
+	}
+	return ctx.result, err
 }
 
 // name returns the name of the command.
 func (c *Command) name() string {
-	return strings.Split(c.Usage, " ")[0]
+	name, _, _ := strings.Cut(c.Usage, " ")
+	return name
 }
 
-// usage returns the command.Usage prefixed by the command path of the parent command.
+// usage returns the command.Usage (or the name and ArgsUsage) prefixed by the command path of the parent command.
+// The "[command]" and "[flags]" parts are appended for intermediate commands that do not mention them (see args).
 func (c *Command) usage() string {
+	usage := strings.TrimSpace(c.name() + " " + c.args())
+	if p := c.parentPath(); p != "" {
+		return p + " " + usage
+	}
+	return usage
+}
+
+// path returns the complete command path, e.g. "printer repeat".
+func (c *Command) path() string {
 	if p := c.parentPath(); p != "" {
-		return p + " " + c.Usage
+		return p + " " + c.name()
+	}
+	return c.name()
+}
+
+// root returns the root of the command tree.
+func (c *Command) root() *Command {
+	if c.parent != nil {
+		return c.parent.root()
 	}
-	return c.Usage
+	return c
 }
 
 // parentPath recurses up the command tree to construct the complete command path of the parent.
@@ -216,23 +974,99 @@ func (c *Command) parentPath() string {
 
 // setParent configures the parent for the current command.
 func (c *Command) setParent(parent *Command) error {
-	c.parent, c.Opts = parent, parent.Opts
+	c.parent = parent
 	return nil
 }
 
-// newFS returns a new pflag.FlagSet with the provided flags.
-func newFS(flags []Flag) *pflag.FlagSet {
+// options returns the options for the command, which are always the options of the root command.
+func (c *Command) options() *Options {
+	return &c.root().Opts
+}
+
+// flagSet returns a pflag.FlagSet with the given flags, as they were defined in the flag set of the command, so that
+// usage texts (and docs) reuse the flag set that was constructed when the command was initialized instead of building
+// a new one. A new flag set is constructed if the command has not been initialized.
+func (c *Command) flagSet(flags []Flag) *pflag.FlagSet {
+	if c.fs == nil {
+		return newFS(flags)
+	}
 	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
 	for _, f := range flags {
-		f.Apply(fs)
+		if pf := c.fs.Lookup(f.GetName()); pf != nil {
+			fs.AddFlag(pf)
+		}
 	}
-	fs.Usage = func() {}
 	return fs
 }
 
-// isUnknownFlagErr returns true if the given pflag.Parse error is due to an unknown flag or shorthand.
-func isUnknownFlagErr(e error) bool {
-	return strings.HasPrefix(e.Error(), "unknown flag") || strings.HasPrefix(e.Error(), "unknown shorthand flag")
+// flagUsages returns the usage texts for the given flags (see pflag.FlagSet.FlagUsages), using the placeholders and
+// default texts of flags that implement PlaceholderFlag or DefaultTextFlag.
+func (c *Command) flagUsages(flags []Flag) string {
+	fs := c.flagSet(flags)
+	usages := pflag.NewFlagSet("", pflag.ContinueOnError)
+	for _, f := range flags {
+		pf := fs.Lookup(f.GetName())
+		if pf == nil {
+			continue
+		}
+		v := &usageValue{Value: pf.Value}
+		if p, ok := f.(PlaceholderFlag); ok && pf.NoOptDefVal == "" {
+			v.placeholder = p.GetPlaceholder()
+		}
+		if def, show := c.usageDefault(f, pf); show && (def != pf.DefValue || !isZeroDefault(pf)) {
+			v.def = def
+		}
+		// The flags are copied so that the usage values do not leak into the flag set of the command.
+		cp := *pf
+		cp.Value, cp.DefValue = v, v.def
+		if decorate := c.options().EnvVarDecorator; decorate != nil && pf.Usage == usageWithEnvVar(f.GetUsage(), f.GetEnvVar()) {
+			cp.Usage = decorateUsage(f.GetUsage(), f.GetEnvVar(), decorate)
+		}
+		usages.AddFlag(&cp)
+	}
+	return usages.FlagUsages()
+}
+
+// usageDefault returns the default value of the flag as it should be shown in usage texts, and false if it should not
+// be shown (see Options.DisableDefaultInUsage and DefaultTextFlag).
+func (c *Command) usageDefault(f Flag, pf *pflag.Flag) (string, bool) {
+	if c.options().DisableDefaultInUsage {
+		return "", false
+	}
+	if d, ok := f.(DefaultTextFlag); ok {
+		if d.IsDefaultInUsageDisabled() {
+			return "", false
+		}
+		if text := d.GetDefaultText(); text != "" {
+			return text, true
+		}
+	}
+	return pf.DefValue, true
+}
+
+// visibleFlags returns the flags that are not hidden.
+func (c *Command) visibleFlags(flags []Flag) []Flag {
+	fs := c.flagSet(flags)
+
+	var visible []Flag
+	for _, f := range flags {
+		if pf := fs.Lookup(f.GetName()); pf != nil && !pf.Hidden {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+// newFS returns a new pflag.FlagSet with the provided flags. Each flag is copied before it is applied, so that the
+// parsed values are stored in the flag set and the Flag itself is left untouched (i.e. its Value is only used as the
+// default). This makes it safe to share flags between commands and to execute the same command multiple times.
+func newFS(flags []Flag) *pflag.FlagSet {
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	for _, f := range flags {
+		copyFlag(f).Apply(fs)
+	}
+	fs.Usage = func() {}
+	return fs
 }
 
 // defaultUsageFunc is the default function used to produce the usage string that is printed when
@@ -244,23 +1078,36 @@ func defaultUsageFunc(c *Command) string {
 		fmt.Fprint(&b, c.Help, "\n\n")
 	}
 
-	fmt.Fprintf(&b, "Usage:\n  %s\n", c.usage())
+	fmt.Fprintf(&b, "%s\n  %s\n", c.tr("Usage:"), c.usage())
 
-	if len(c.Subcommands) > 0 {
-		fmt.Fprint(&b, "\nAvailable Commands:\n")
+	if subcommands := c.visibleSubcommands(); len(subcommands) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", c.tr("Available Commands:"))
 		tw := tabwriter.NewWriter(&b, 0, 2, 8, ' ', 0)
-		for _, subcommand := range c.Subcommands {
+		for _, subcommand := range subcommands {
 			fmt.Fprintf(tw, "  %s\t%s\n", subcommand.name(), subcommand.Help)
 		}
 		tw.Flush()
 	}
 
-	if flags := c.LocalFlags(); len(flags) > 0 {
-		fmt.Fprintf(&b, "\nFlags:\n%s", newFS(flags).FlagUsages())
+	if usages := c.flagUsages(c.ownFlags()); usages != "" {
+		fmt.Fprintf(&b, "\n%s\n%s", c.tr("Flags:"), usages)
 	}
 
-	if flags := c.GlobalFlags(); len(flags) > 0 {
-		fmt.Fprintf(&b, "\nGlobal Flags:\n%s", newFS(flags).FlagUsages())
+	for i, group := range c.groups {
+		if usages := c.flagUsages(group); usages != "" {
+			fmt.Fprintf(&b, "\n%s\n%s", fmt.Sprintf(c.tr("%s Flags:"), c.FlagGroups[i].Name), usages)
+		}
+	}
+
+	if usages := c.flagUsages(c.GlobalFlags()); usages != "" {
+		fmt.Fprintf(&b, "\n%s\n%s", c.tr("Global Flags:"), usages)
+	}
+
+	if c.Examples != "" {
+		fmt.Fprintf(&b, "\n%s\n", c.tr("Examples:"))
+		for _, line := range strings.Split(strings.TrimSpace(c.Examples), "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
 	}
 
 	return b.String()
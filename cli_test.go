@@ -1,9 +1,15 @@
 package cli_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/itsdalmo/cli"
@@ -226,3 +232,1433 @@ func eq(t *testing.T, expected, got interface{}) {
 		t.Errorf("\nexpected:\n%v\n\ngot:\n%v", expected, got)
 	}
 }
+
+func Test_Subcommands_PassArgsAfterDashVerbatim(t *testing.T) {
+	tests := []struct {
+		description           string
+		args                  []string
+		expectedArgs          []string
+		expectedArgsAfterDash []string
+		expectedErr           bool
+	}{
+		{
+			description:           "flags after dash",
+			args:                  []string{"nested", "subcommand", "arg", "--", "--debug", "subcommand"},
+			expectedArgs:          []string{"arg", "--debug", "subcommand"},
+			expectedArgsAfterDash: []string{"--debug", "subcommand"},
+		},
+		{
+			description:           "dash before subcommand arguments",
+			args:                  []string{"--debug", "nested", "subcommand", "--", "-x"},
+			expectedArgs:          []string{"-x"},
+			expectedArgsAfterDash: []string{"-x"},
+		},
+		{
+			description: "subcommand name after dash",
+			args:        []string{"nested", "--", "subcommand"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "root [flags] [command]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "debug, d",
+						Usage: "Enable debug logging",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "nested",
+						Subcommands: []*cli.Command{
+							{
+								Usage: "subcommand",
+								Exec: func(c *cli.Context) error {
+									eq(t, tc.expectedArgs, c.Args())
+									eq(t, tc.expectedArgsAfterDash, c.ArgsAfterDash())
+									return nil
+								},
+							},
+						},
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			eq(t, tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func Test_Subcommands_DisableInterspersed(t *testing.T) {
+	c := cli.Command{
+		Usage: "root [flags] [command]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug, d",
+				Usage: "Enable debug logging",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage:               "run [flags] <program> [<arg>...]",
+				DisableInterspersed: true,
+				Exec: func(c *cli.Context) error {
+					debug, err := c.GetBool("debug")
+					eq(t, nil, err)
+					eq(t, true, debug)
+					eq(t, []string{"myprog", "--debug", "--flag-for-myprog"}, c.Args())
+					return nil
+				},
+			},
+		},
+	}
+
+	if err := c.Execute([]string{"--debug", "run", "myprog", "--debug", "--flag-for-myprog"}); err != nil {
+		t.Errorf("execute error: %s", err)
+	}
+}
+
+func Test_Subcommands_SkipFlagParsing(t *testing.T) {
+	c := cli.Command{
+		Usage: "root [flags] [command]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug, d",
+				Usage: "Enable debug logging",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage:           "exec <program> [<arg>...]",
+				SkipFlagParsing: true,
+				Exec: func(c *cli.Context) error {
+					debug, err := c.GetBool("debug")
+					eq(t, nil, err)
+					eq(t, true, debug)
+					eq(t, []string{"--debug", "ls", "-la", "--", "--help"}, c.Args())
+					return nil
+				},
+			},
+		},
+	}
+
+	if err := c.Execute([]string{"-d", "exec", "--debug", "ls", "-la", "--", "--help"}); err != nil {
+		t.Errorf("execute error: %s", err)
+	}
+}
+
+func Test_Subcommands_ForwardUnknownFlags(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+	}{
+		{
+			description: "flag value with equals sign",
+			args:        []string{"--times=3", "--region", "eu-north-1", "repeat", "hello"},
+		},
+		{
+			description: "grouped shorthands",
+			args:        []string{"-dt3", "--region=eu-north-1", "repeat", "hello"},
+		},
+		{
+			description: "parent flags after subcommand",
+			args:        []string{"repeat", "hello", "-t", "3", "--region", "eu-north-1", "-d"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "root [flags] [command]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "debug, d",
+						Usage: "Enable debug logging",
+					},
+					&cli.StringFlag{
+						Name:     "region",
+						Usage:    "AWS Region to target",
+						Required: true,
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "repeat [flags] <arg>",
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:  "times, t",
+								Usage: "Number of times to print the argument",
+							},
+						},
+						Exec: func(c *cli.Context) error {
+							times, err := c.GetInt("times")
+							eq(t, nil, err)
+							eq(t, 3, times)
+
+							region, err := c.GetString("region")
+							eq(t, nil, err)
+							eq(t, "eu-north-1", region)
+
+							eq(t, []string{"hello"}, c.Args())
+							return nil
+						},
+					},
+				},
+			}
+			if err := c.Execute(tc.args); err != nil {
+				t.Errorf("execute error: %s", err)
+			}
+		})
+	}
+}
+
+func Test_NestedSubcommands_FlagValuesBeforeSubcommand(t *testing.T) {
+	c := cli.Command{
+		Usage: "root [flags] [command]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "profile, p",
+				Usage: "Profile to use",
+			},
+			&cli.StringSliceFlag{
+				Name:  "instance, i",
+				Usage: "An instance to target",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "nested",
+				Subcommands: []*cli.Command{
+					{
+						Usage: "subcommand",
+						Exec: func(c *cli.Context) error {
+							profile, err := c.GetString("profile")
+							eq(t, nil, err)
+							eq(t, "nested", profile)
+
+							instances, err := c.GetStringSlice("instance")
+							eq(t, nil, err)
+							eq(t, []string{"i-1", "i-2", "i-3"}, instances)
+
+							eq(t, []string{"arg"}, c.Args())
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := c.Execute([]string{"--profile", "nested", "-i", "i-1", "nested", "-ii-2", "subcommand", "arg", "--instance=i-3"}); err != nil {
+		t.Errorf("execute error: %s", err)
+	}
+}
+
+func Test_NegativeNumbersAsArguments(t *testing.T) {
+	tests := []struct {
+		description  string
+		args         []string
+		expectedArgs []string
+		expectedBy   int
+		expectedErr  bool
+	}{
+		{
+			description:  "integers and decimals",
+			args:         []string{"-5", "-0.5", "-.25"},
+			expectedArgs: []string{"-5", "-0.5", "-.25"},
+		},
+		{
+			description:  "mixed with flags",
+			args:         []string{"-5", "--by", "-2", "3", "-v"},
+			expectedArgs: []string{"-5", "3"},
+			expectedBy:   -2,
+		},
+		{
+			description:  "numeric shorthand takes precedence",
+			args:         []string{"-1", "-7"},
+			expectedArgs: []string{"-7"},
+		},
+		{
+			description: "unknown shorthand",
+			args:        []string{"-x"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "adjust [flags] <number>...",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "by",
+						Usage: "Adjust by",
+					},
+					&cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Verbose output",
+					},
+					&cli.BoolFlag{
+						Name:  "one, 1",
+						Usage: "Flag with a numeric shorthand",
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					by, err := c.GetInt("by")
+					eq(t, nil, err)
+					eq(t, tc.expectedBy, by)
+					eq(t, tc.expectedArgs, c.Args())
+					return nil
+				},
+			}
+			err := c.Execute(tc.args)
+			eq(t, tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func Test_GroupedShorthands(t *testing.T) {
+	tests := []struct {
+		description     string
+		args            []string
+		expectedArgs    []string
+		expectedDebug   bool
+		expectedVerbose int
+		expectedTimes   int
+		expectedFile    string
+		expectedErr     bool
+	}{
+		{
+			description:   "bool with value in group",
+			args:          []string{"-dt3"},
+			expectedDebug: true,
+			expectedTimes: 3,
+		},
+		{
+			description:   "bool with value after group",
+			args:          []string{"-dt", "3"},
+			expectedDebug: true,
+			expectedTimes: 3,
+		},
+		{
+			description:   "bool with assigned value in group",
+			args:          []string{"-dt=3"},
+			expectedDebug: true,
+			expectedTimes: 3,
+		},
+		{
+			description:   "string value in group",
+			args:          []string{"-dffoo"},
+			expectedDebug: true,
+			expectedFile:  "foo",
+		},
+		{
+			description:   "assigned string value in group",
+			args:          []string{"-df=foo"},
+			expectedDebug: true,
+			expectedFile:  "foo",
+		},
+		{
+			description:  "empty assigned value",
+			args:         []string{"-f=", "x"},
+			expectedArgs: []string{"x"},
+		},
+		{
+			description:  "value consumes the rest of the group",
+			args:         []string{"-fd"},
+			expectedFile: "d",
+		},
+		{
+			description:  "value that looks like a flag",
+			args:         []string{"-f", "-d"},
+			expectedFile: "-d",
+		},
+		{
+			description:   "negative value in group",
+			args:          []string{"-t-5"},
+			expectedTimes: -5,
+		},
+		{
+			description:   "negative value after group",
+			args:          []string{"-dt", "-5"},
+			expectedDebug: true,
+			expectedTimes: -5,
+		},
+		{
+			description:   "explicit bool value",
+			args:          []string{"-d=false"},
+			expectedDebug: false,
+		},
+		{
+			description:     "repeated count",
+			args:            []string{"-vvd"},
+			expectedDebug:   true,
+			expectedVerbose: 2,
+		},
+		{
+			description:     "count after bool",
+			args:            []string{"-dvv", "-v"},
+			expectedDebug:   true,
+			expectedVerbose: 3,
+		},
+		{
+			description:   "interspersed with arguments",
+			args:          []string{"a", "-dt3", "b"},
+			expectedArgs:  []string{"a", "b"},
+			expectedDebug: true,
+			expectedTimes: 3,
+		},
+		{
+			description:  "group after terminator",
+			args:         []string{"--", "-dt3"},
+			expectedArgs: []string{"-dt3"},
+		},
+		{
+			description:  "group as value of long flag",
+			args:         []string{"--file", "-dt3"},
+			expectedFile: "-dt3",
+		},
+		{
+			description:   "negative number argument",
+			args:          []string{"-d", "-5"},
+			expectedArgs:  []string{"-5"},
+			expectedDebug: true,
+		},
+		{
+			description: "missing value",
+			args:        []string{"-dt"},
+			expectedErr: true,
+		},
+		{
+			description: "unknown shorthand in group",
+			args:        []string{"-dx"},
+			expectedErr: true,
+		},
+		{
+			description: "invalid value in group",
+			args:        []string{"-tx"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var executed bool
+			c := cli.Command{
+				Usage: "run [flags] [args...]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "debug, d",
+						Usage: "Debug output",
+					},
+					&cli.CountFlag{
+						Name:  "verbose, v",
+						Usage: "Verbosity",
+					},
+					&cli.IntFlag{
+						Name:  "times, t",
+						Usage: "Number of times",
+					},
+					&cli.StringFlag{
+						Name:  "file, f",
+						Usage: "File",
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					executed = true
+					debug, err := c.GetBool("debug")
+					eq(t, nil, err)
+					verbose, err := c.GetCount("verbose")
+					eq(t, nil, err)
+					times, err := c.GetInt("times")
+					eq(t, nil, err)
+					file, err := c.GetString("file")
+					eq(t, nil, err)
+
+					eq(t, tc.expectedDebug, debug)
+					eq(t, tc.expectedVerbose, verbose)
+					eq(t, tc.expectedTimes, times)
+					eq(t, tc.expectedFile, file)
+					eq(t, tc.expectedArgs, append([]string(nil), c.Args()...))
+					return nil
+				},
+			}
+			err := c.Execute(tc.args)
+			eq(t, tc.expectedErr, err != nil)
+			eq(t, !tc.expectedErr, executed)
+		})
+	}
+}
+
+func Test_StrictPOSIX(t *testing.T) {
+	tests := []struct {
+		description   string
+		args          []string
+		expectedArgs  []string
+		expectedDebug bool
+		expectedFile  string
+		expectedErr   string
+	}{
+		{
+			description:   "flags before operands",
+			args:          []string{"run", "-d", "-f", "x", "a", "b"},
+			expectedArgs:  []string{"a", "b"},
+			expectedDebug: true,
+			expectedFile:  "x",
+		},
+		{
+			description:  "flags after operands",
+			args:         []string{"run", "a", "-d", "--file", "x"},
+			expectedArgs: []string{"a", "-d", "--file", "x"},
+		},
+		{
+			description:   "terminator",
+			args:          []string{"run", "-d", "--", "-f", "--"},
+			expectedArgs:  []string{"-f", "--"},
+			expectedDebug: true,
+		},
+		{
+			description:  "terminator as value",
+			args:         []string{"run", "-f", "--", "a"},
+			expectedArgs: []string{"a"},
+			expectedFile: "--",
+		},
+		{
+			description:   "verbatim shorthand value",
+			args:          []string{"run", "-df=x"},
+			expectedDebug: true,
+			expectedFile:  "=x",
+		},
+		{
+			description: "assigned boolean shorthand",
+			args:        []string{"run", "-d=false"},
+			expectedErr: "app run: unknown flag: -=",
+		},
+		{
+			description: "unknown flag",
+			args:        []string{"run", "--bogus", "-x"},
+			expectedErr: "app run: unknown flag: --bogus",
+		},
+		{
+			description: "missing value",
+			args:        []string{"run", "-f"},
+			expectedErr: "app run: flag needs an argument: -f",
+		},
+		{
+			description: "unknown subcommand flag",
+			args:        []string{"-x", "run"},
+			expectedErr: "app run: unknown flag: -x",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				args  []string
+				debug bool
+				file  string
+			)
+			c := cli.Command{
+				Usage: "app [command]",
+				Flags: []cli.Flag{&cli.BoolFlag{Name: "debug, d"}},
+				Opts:  cli.Options{StrictPOSIX: true, ErrWriter: io.Discard},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "run [flags] [args...]",
+						Flags: []cli.Flag{&cli.StringFlag{Name: "file, f"}},
+						Exec: func(c *cli.Context) error {
+							args = c.Args()
+							debug, _ = c.GetBool("debug")
+							file, _ = c.GetString("file")
+							return nil
+						},
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				eq(t, tc.expectedErr, err.Error())
+				return
+			}
+			eq(t, nil, err)
+			eq(t, tc.expectedArgs, append([]string(nil), args...))
+			eq(t, tc.expectedDebug, debug)
+			eq(t, tc.expectedFile, file)
+		})
+	}
+}
+
+func Test_ResponseFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.txt")
+	if err := ioutil.WriteFile(path, []byte("# Arguments for the subcommand\nsubcommand\n--instance\ni-1 with spaces\n\n-i=i-2\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := cli.Command{
+		Usage: "root [flags] [command]",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "instance, i",
+				Usage: "An instance to target",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "subcommand",
+				Exec: func(c *cli.Context) error {
+					instances, err := c.GetStringSlice("instance")
+					eq(t, nil, err)
+					eq(t, []string{"i-1 with spaces", "i-2"}, instances)
+					eq(t, []string{"@literal", "@" + path}, c.Args())
+					return nil
+				},
+			},
+		},
+		Opts: cli.Options{
+			ResponseFiles: true,
+		},
+	}
+
+	if err := c.Execute([]string{"@" + path, "@@literal", "--", "@" + path}); err != nil {
+		t.Errorf("execute error: %s", err)
+	}
+}
+
+func Test_ArgsPreprocessor(t *testing.T) {
+	var (
+		args    []string
+		rawArgs []string
+		env     string
+	)
+	c := cli.Command{
+		Usage: "root [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "env"}},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [flags] [args...]",
+				Exec: func(c *cli.Context) error {
+					args = c.Args()
+					rawArgs = c.RawArgs()
+					env, _ = c.GetString("env")
+					return nil
+				},
+			},
+		},
+		Opts: cli.Options{
+			ArgsPreprocessor: func(args []string) ([]string, error) {
+				for _, arg := range args {
+					if arg == "--fail" {
+						return nil, errors.New("preprocessing failed")
+					}
+				}
+				return append([]string{"--env", "prod"}, args...), nil
+			},
+		},
+	}
+
+	eq(t, nil, c.Execute([]string{"deploy", "a"}))
+	eq(t, []string{"a"}, args)
+	eq(t, []string{"deploy", "a"}, rawArgs)
+	eq(t, "prod", env)
+
+	err := c.Execute([]string{"deploy", "--fail"})
+	eq(t, "parsing command: preprocessing failed", fmt.Sprint(err))
+}
+
+func Test_ExecuteRepeatedly(t *testing.T) {
+	var (
+		b       strings.Builder
+		results []string
+	)
+	c := cli.Command{
+		Usage:   "root [flags] [command]",
+		Version: "1.0.0",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug, d",
+				Usage: "Enable debug logging",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "subcommand [flags]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "instance, i",
+						Usage: "An instance to target",
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					debug, err := c.GetBool("debug")
+					eq(t, nil, err)
+					instances, err := c.GetStringSlice("instance")
+					eq(t, nil, err)
+					results = append(results, fmt.Sprintf("debug=%t changed=%t instances=%v", debug, c.Changed("debug"), instances))
+					return nil
+				},
+			},
+		},
+		Opts: cli.Options{
+			Writer:    &b,
+			ErrWriter: &b,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		eq(t, nil, c.Execute([]string{"subcommand", "-d", "-i", "i-1", "-i", "i-2"}))
+		eq(t, nil, c.Execute([]string{"subcommand", "--help"}))
+		eq(t, nil, c.Execute([]string{"subcommand"}))
+	}
+	eq(t, []string{
+		"debug=true changed=true instances=[i-1 i-2]",
+		"debug=false changed=false instances=[]",
+		"debug=true changed=true instances=[i-1 i-2]",
+		"debug=false changed=false instances=[]",
+	}, results)
+	eq(t, 2, strings.Count(b.String(), "Usage:\n  root subcommand [flags]\n"))
+
+	b.Reset()
+	eq(t, nil, c.Execute([]string{"--help"}))
+	eq(t, 1, strings.Count(b.String(), "  version "))
+}
+
+func Test_ValidateFlags(t *testing.T) {
+	tests := []struct {
+		description string
+		rootFlags   []cli.Flag
+		flags       []cli.Flag
+		expectedErr string
+	}{
+		{
+			description: "duplicate name",
+			flags: []cli.Flag{
+				&cli.StringFlag{Name: "region"},
+				&cli.StringSliceFlag{Name: "region"},
+			},
+			expectedErr: `misconfigured command "root subcommand": flag "region" is already defined (locally)`,
+		},
+		{
+			description: "duplicate shorthand",
+			flags: []cli.Flag{
+				&cli.StringFlag{Name: "region, r"},
+				&cli.IntFlag{Name: "replicas, r"},
+			},
+			expectedErr: `misconfigured command "root subcommand": shorthand "r" for flag "replicas" is already used (by flag "region")`,
+		},
+		{
+			description: "redefines inherited flag",
+			rootFlags:   []cli.Flag{&cli.BoolFlag{Name: "debug, d"}},
+			flags:       []cli.Flag{&cli.BoolFlag{Name: "debug"}},
+			expectedErr: `misconfigured command "root subcommand": flag "debug" is already defined (inherited from "root")`,
+		},
+		{
+			description: "redefines inherited shorthand",
+			rootFlags:   []cli.Flag{&cli.BoolFlag{Name: "debug, d"}},
+			flags:       []cli.Flag{&cli.BoolFlag{Name: "dry-run, d"}},
+			expectedErr: `misconfigured command "root subcommand": shorthand "d" for flag "dry-run" is already used (by flag "debug" inherited from "root")`,
+		},
+		{
+			description: "invalid shorthand",
+			flags:       []cli.Flag{&cli.BoolFlag{Name: "dry-run, dr"}},
+			expectedErr: `misconfigured command "root subcommand": shorthand "dr" for flag "dry-run" must be a single character`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "root [flags] [command]",
+				Flags: tc.rootFlags,
+				Subcommands: []*cli.Command{
+					{
+						Usage: "subcommand [flags]",
+						Flags: tc.flags,
+						Exec:  func(c *cli.Context) error { return nil },
+					},
+				},
+			}
+			err := c.Execute([]string{"subcommand"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var misconfigured *cli.ErrMisconfigured
+			eq(t, true, errors.As(err, &misconfigured))
+			eq(t, tc.expectedErr, misconfigured.Error())
+		})
+	}
+}
+
+func Test_OptionsOnlyOnRoot(t *testing.T) {
+	t.Run("subcommands use root options", func(t *testing.T) {
+		var b bytes.Buffer
+		c := cli.Command{
+			Usage: "root [command]",
+			Opts:  cli.Options{Writer: &b},
+			Subcommands: []*cli.Command{
+				{
+					Usage: "subcommand",
+					Exec: func(c *cli.Context) error {
+						return c.Print("ok")
+					},
+				},
+			},
+		}
+		for i := 0; i < 2; i++ {
+			if err := c.Execute([]string{"subcommand"}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		eq(t, "ok\nok\n", b.String())
+	})
+
+	t.Run("options on subcommand", func(t *testing.T) {
+		c := cli.Command{
+			Usage: "root [command]",
+			Subcommands: []*cli.Command{
+				{
+					Usage: "subcommand",
+					Opts:  cli.Options{ErrWriter: io.Discard},
+					Exec:  func(c *cli.Context) error { return nil },
+				},
+			},
+		}
+		err := c.Execute([]string{"subcommand"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		eq(t, `parsing command: misconfigured command "root subcommand": options can only be set on the root command`, err.Error())
+	})
+}
+
+func Test_ValidateSubcommands(t *testing.T) {
+	exec := func(c *cli.Context) error { return nil }
+	tests := []struct {
+		description string
+		subcommands []*cli.Command
+		expectedErr string
+	}{
+		{
+			description: "duplicate names",
+			subcommands: []*cli.Command{
+				{Usage: "list [flags]", Exec: exec},
+				{Usage: "list <name>", Exec: exec},
+			},
+			expectedErr: `parsing command: misconfigured command "root": subcommand "list" is defined more than once`,
+		},
+		{
+			description: "leading space",
+			subcommands: []*cli.Command{
+				{Usage: " list", Exec: exec},
+			},
+			expectedErr: `parsing command: misconfigured command "root ": usage must start with a valid command name: " list"`,
+		},
+		{
+			description: "leading dash",
+			subcommands: []*cli.Command{
+				{Usage: "--list", Exec: exec},
+			},
+			expectedErr: `parsing command: misconfigured command "root --list": usage must start with a valid command name: "--list"`,
+		},
+		{
+			description: "arguments in both usage and args usage",
+			subcommands: []*cli.Command{
+				{Usage: "list [flags]", ArgsUsage: "[flags]", Exec: exec},
+			},
+			expectedErr: `parsing command: misconfigured command "root list": usage cannot contain arguments when args usage is defined: "list [flags]"`,
+		},
+		{
+			description: "unbalanced args usage",
+			subcommands: []*cli.Command{
+				{Usage: "list", ArgsUsage: "[flags] <name", Exec: exec},
+			},
+			expectedErr: `parsing command: misconfigured command "root list": invalid args usage "[flags] <name": unclosed '<'`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{Usage: "root [command]", Subcommands: tc.subcommands}
+			err := c.Execute([]string{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			eq(t, tc.expectedErr, err.Error())
+		})
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		check       func(t *testing.T, err error)
+	}{
+		{
+			description: "unknown flag",
+			args:        []string{"echo", "--bogus", "value"},
+			check: func(t *testing.T, err error) {
+				var e *cli.ErrUnknownFlag
+				eq(t, true, errors.As(err, &e))
+				eq(t, "--bogus", e.Flag)
+			},
+		},
+		{
+			description: "unknown shorthand in group",
+			args:        []string{"echo", "-dx"},
+			check: func(t *testing.T, err error) {
+				var e *cli.ErrUnknownFlag
+				eq(t, true, errors.As(err, &e))
+				eq(t, "-x", e.Flag)
+				eq(t, "parsing command: unknown flag: -x", err.Error())
+			},
+		},
+		{
+			description: "missing value",
+			args:        []string{"echo", "--count"},
+			check: func(t *testing.T, err error) {
+				var e *cli.ErrMissingFlagValue
+				eq(t, true, errors.As(err, &e))
+				eq(t, "--count", e.Flag)
+			},
+		},
+		{
+			description: "invalid value",
+			args:        []string{"--debug", "echo", "-n", "abc"},
+			check: func(t *testing.T, err error) {
+				var e *cli.ErrInvalidFlagValue
+				eq(t, true, errors.As(err, &e))
+				eq(t, "count", e.Name)
+				eq(t, "abc", e.Value)
+			},
+		},
+		{
+			description: "help before unknown flag",
+			args:        []string{"echo", "--help", "--bogus"},
+			check: func(t *testing.T, err error) {
+				eq(t, nil, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "root [command]",
+				Flags: []cli.Flag{&cli.BoolFlag{Name: "debug, d"}},
+				Opts:  cli.Options{ErrWriter: io.Discard},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "echo [flags]",
+						Flags: []cli.Flag{&cli.IntFlag{Name: "count, n"}},
+						Exec:  func(c *cli.Context) error { return nil },
+					},
+				},
+			}
+			tc.check(t, c.Execute(tc.args))
+		})
+	}
+}
+
+func Test_RequiredFlagsOnlyForExecutedCommand(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expectedErr string
+	}{
+		{
+			description: "help for subcommand",
+			args:        []string{"sub", "--help"},
+		},
+		{
+			description: "help for nested subcommand",
+			args:        []string{"sub", "nested", "-h"},
+		},
+		{
+			description: "no subcommand",
+			args:        []string{"sub"},
+			expectedErr: "parsing command: root sub: no subcommand specified (available: nested). See --help",
+		},
+		{
+			description: "executed command",
+			args:        []string{"sub", "nested"},
+			expectedErr: "parsing command: missing required flags [token]",
+		},
+		{
+			description: "required flag given",
+			args:        []string{"--token", "secret", "sub", "nested"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "root [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "token", Required: true}},
+				Opts:  cli.Options{ErrWriter: io.Discard},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "sub [command]",
+						Subcommands: []*cli.Command{
+							{
+								Usage: "nested",
+								Exec:  func(c *cli.Context) error { return nil },
+							},
+						},
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr == "" {
+				eq(t, nil, err)
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			eq(t, tc.expectedErr, err.Error())
+		})
+	}
+}
+
+func Test_HelpAtAnyDepth(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expected    string
+	}{
+		{
+			description: "root",
+			args:        []string{"--help"},
+			expected:    "Usage:\n  root [command]\n",
+		},
+		{
+			description: "root shorthand",
+			args:        []string{"-h"},
+			expected:    "Usage:\n  root [command]\n",
+		},
+		{
+			description: "intermediate",
+			args:        []string{"sub", "--help"},
+			expected:    "Usage:\n  root sub [command]\n",
+		},
+		{
+			description: "intermediate with help before subcommand",
+			args:        []string{"--help", "sub"},
+			expected:    "Usage:\n  root sub [command]\n",
+		},
+		{
+			description: "leaf",
+			args:        []string{"sub", "nested", "--help"},
+			expected:    "Usage:\n  root sub nested\n",
+		},
+		{
+			description: "leaf with help before unknown flag",
+			args:        []string{"sub", "nested", "-h", "--bogus"},
+			expected:    "Usage:\n  root sub nested\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "root [command]",
+				Opts:  cli.Options{ErrWriter: &b},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "sub [command]",
+						Subcommands: []*cli.Command{
+							{
+								Usage: "nested",
+								Exec: func(c *cli.Context) error {
+									t.Fatal("exec should not be called")
+									return nil
+								},
+							},
+						},
+					},
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, true, strings.HasPrefix(b.String(), tc.expected))
+		})
+	}
+}
+
+func Test_UsageUsesParsedFlagSet(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "root [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region", Value: "eu-west-1"}},
+		Opts:  cli.Options{ErrWriter: &b},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "echo",
+				Flags: []cli.Flag{&cli.IntFlag{Name: "count, n", Value: 1}},
+				Exec:  func(c *cli.Context) error { return nil },
+			},
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--region", "us-east-1", "echo", "-n", "3", "--help"}))
+	eq(t, true, strings.Contains(b.String(), "Flags:\n  -n, --count int    (default 1)\n"))
+	eq(t, true, strings.Contains(b.String(), "Global Flags:\n      --region string    (default \"eu-west-1\")\n"))
+}
+
+func Test_Aliases(t *testing.T) {
+	tests := []struct {
+		description  string
+		args         []string
+		expectedArgs []string
+		expectedErr  string
+	}{
+		{
+			description:  "expands alias",
+			args:         []string{"co", "main"},
+			expectedArgs: []string{"main"},
+		},
+		{
+			description:  "expands alias after global flags",
+			args:         []string{"--dir", "repo", "co", "main"},
+			expectedArgs: []string{"main"},
+		},
+		{
+			description:  "quoted arguments",
+			args:         []string{"cm"},
+			expectedArgs: []string{"fix: it's done"},
+		},
+		{
+			description:  "arguments are not expanded",
+			args:         []string{"checkout", "co"},
+			expectedArgs: []string{"co"},
+		},
+		{
+			description: "unterminated quote",
+			args:        []string{"broken"},
+			expectedErr: `parsing command: alias "broken": unterminated quote in "checkout 'main"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var args []string
+			exec := func(c *cli.Context) error {
+				args = c.Args()
+				return nil
+			}
+			c := cli.Command{
+				Usage: "git [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "dir"}},
+				Opts: cli.Options{
+					Aliases: map[string]string{
+						"co":     "checkout --track",
+						"cm":     `commit -m "fix: it's done"`,
+						"broken": "checkout 'main",
+					},
+				},
+				Subcommands: []*cli.Command{
+					{Usage: "checkout [flags] <branch>", Flags: []cli.Flag{&cli.BoolFlag{Name: "track"}}, Exec: exec},
+					{Usage: "commit [flags]", Flags: []cli.Flag{&cli.StringFlag{Name: "message, m"}}, Exec: func(c *cli.Context) error {
+						m, err := c.GetString("message")
+						args = []string{m}
+						return err
+					}},
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				eq(t, tc.expectedErr, err.Error())
+				return
+			}
+			eq(t, nil, err)
+			eq(t, tc.expectedArgs, args)
+		})
+	}
+
+	t.Run("conflicts with subcommand", func(t *testing.T) {
+		c := cli.Command{
+			Usage: "git [command]",
+			Opts:  cli.Options{Aliases: map[string]string{"checkout": "checkout --track"}},
+			Subcommands: []*cli.Command{
+				{Usage: "checkout", Exec: func(c *cli.Context) error { return nil }},
+			},
+		}
+		err := c.Execute([]string{"checkout"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		eq(t, `parsing command: misconfigured command "git": alias "checkout" conflicts with a subcommand`, err.Error())
+	})
+}
+
+func Test_ValidArgs(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expectedErr string
+	}{
+		{
+			description: "valid argument",
+			args:        []string{"get", "pods", "web"},
+		},
+		{
+			description: "no arguments",
+			args:        []string{"get"},
+		},
+		{
+			description: "invalid argument",
+			args:        []string{"get", "pod"},
+			expectedErr: `parsing command: invalid argument "pod" for "kubectl get", expected one of: pods, services, nodes`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "kubectl [command]",
+				Subcommands: []*cli.Command{
+					{
+						Usage:     "get <resource> [name]",
+						ValidArgs: []string{"pods", "services", "nodes"},
+						Exec:      func(c *cli.Context) error { return nil },
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				eq(t, tc.expectedErr, err.Error())
+				return
+			}
+			eq(t, nil, err)
+
+			spec, err := cli.NewSpec(&c)
+			eq(t, nil, err)
+			eq(t, []string{"pods", "services", "nodes"}, spec.Subcommands[0].ValidArgs)
+		})
+	}
+
+	t.Run("with subcommands", func(t *testing.T) {
+		c := cli.Command{
+			Usage:       "kubectl [command]",
+			ValidArgs:   []string{"pods"},
+			Subcommands: []*cli.Command{{Usage: "get", Exec: func(c *cli.Context) error { return nil }}},
+		}
+		err := c.Execute(nil)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		eq(t, `parsing command: misconfigured command "kubectl": cannot define both valid args and subcommands`, err.Error())
+	})
+}
+
+func Test_ArgsUsage(t *testing.T) {
+	var help bytes.Buffer
+	c := cli.Command{
+		Usage: "deployer [command]",
+		Opts:  cli.Options{ErrWriter: &help},
+		Subcommands: []*cli.Command{
+			{Usage: "deploy", ArgsUsage: "[flags] <service>...", Exec: func(c *cli.Context) error { return nil }},
+		},
+	}
+	eq(t, nil, c.Execute([]string{"deploy", "--help"}))
+	eq(t, true, strings.Contains(help.String(), "Usage:\n  deployer deploy [flags] <service>...\n"))
+
+	spec, err := cli.NewSpec(&c)
+	eq(t, nil, err)
+	eq(t, "deploy", spec.Subcommands[0].Name)
+	eq(t, "deployer deploy [flags] <service>...", spec.Subcommands[0].Usage)
+	eq(t, "[flags] <service>...", spec.Subcommands[0].Args)
+}
+
+func Test_HelpFlag(t *testing.T) {
+	tests := []struct {
+		description  string
+		helpFlag     string
+		disable      bool
+		args         []string
+		expectedHelp bool
+		expectedHost string
+		expectedErr  string
+	}{
+		{
+			description:  "default",
+			args:         []string{"connect", "-h"},
+			expectedHelp: true,
+		},
+		{
+			description:  "renamed",
+			helpFlag:     "usage, ?",
+			args:         []string{"connect", "-?"},
+			expectedHelp: true,
+		},
+		{
+			description: "renamed rejects the default",
+			helpFlag:    "usage, ?",
+			args:        []string{"connect", "--help"},
+			expectedErr: "parsing command: unknown flag: --help",
+		},
+		{
+			description: "long only",
+			helpFlag:    "help",
+			args:        []string{"connect", "-h"},
+			expectedErr: "parsing command: unknown flag: -h",
+		},
+		{
+			description:  "disabled",
+			disable:      true,
+			args:         []string{"connect", "-h", "example.com"},
+			expectedHost: "example.com",
+		},
+		{
+			description: "disabled long flag",
+			disable:     true,
+			args:        []string{"connect", "--help"},
+			expectedErr: "parsing command: unknown flag: --help",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				help bytes.Buffer
+				host string
+			)
+			flags := []cli.Flag{&cli.StringFlag{Name: "port, p"}}
+			if tc.disable {
+				flags = append(flags, &cli.StringFlag{Name: "host, h"})
+			}
+			c := cli.Command{
+				Usage: "ssh [command]",
+				Opts:  cli.Options{ErrWriter: &help, HelpFlag: tc.helpFlag},
+				Subcommands: []*cli.Command{
+					{
+						Usage:           "connect [flags]",
+						Flags:           flags,
+						DisableHelpFlag: tc.disable,
+						Exec: func(c *cli.Context) error {
+							host, _ = c.GetString("host")
+							return nil
+						},
+					},
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				eq(t, tc.expectedErr, err.Error())
+				return
+			}
+			eq(t, nil, err)
+			eq(t, tc.expectedHelp, strings.Contains(help.String(), "Usage:"))
+			eq(t, tc.expectedHost, host)
+		})
+	}
+}
+
+func Test_IntermediateUsage(t *testing.T) {
+	exec := func(c *cli.Context) error { return nil }
+	c := cli.Command{
+		Usage: "cloud",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region"}},
+		Subcommands: []*cli.Command{
+			{
+				Usage:       "compute",
+				Subcommands: []*cli.Command{{Usage: "list", Exec: exec}},
+			},
+			{
+				Usage:       "storage [command]",
+				Subcommands: []*cli.Command{{Usage: "list", Exec: exec}},
+			},
+			{
+				Usage:       "network [flags]",
+				Subcommands: []*cli.Command{{Usage: "list", Exec: exec}},
+			},
+			{
+				Usage:       "dns <command> [flags]",
+				Subcommands: []*cli.Command{{Usage: "list", Exec: exec}},
+			},
+		},
+	}
+	spec, err := cli.NewSpec(&c)
+	eq(t, nil, err)
+	eq(t, "cloud [command] [flags]", spec.Usage)
+	eq(t, "cloud compute [command] [flags]", spec.Subcommands[0].Usage)
+	eq(t, "cloud storage [command] [flags]", spec.Subcommands[1].Usage)
+	eq(t, "cloud network [flags] [command]", spec.Subcommands[2].Usage)
+	eq(t, "cloud dns <command> [flags]", spec.Subcommands[3].Usage)
+	eq(t, "cloud compute list", spec.Subcommands[0].Subcommands[0].Usage)
+
+	t.Run("without flags", func(t *testing.T) {
+		c := cli.Command{Usage: "cloud", Subcommands: []*cli.Command{{Usage: "list", Exec: exec}}}
+		spec, err := cli.NewSpec(&c)
+		eq(t, nil, err)
+		eq(t, "cloud [command]", spec.Usage)
+	})
+}
+
+func Test_ExecuteWithIO(t *testing.T) {
+	var original bytes.Buffer
+	c := cli.Command{
+		Usage: "greet [flags]",
+		Opts:  cli.Options{Writer: &original},
+		Exec: func(c *cli.Context) error {
+			name, err := c.Prompt("Name:")
+			if err != nil {
+				return err
+			}
+			c.Printf("Hello, %s!\n", name)
+			return nil
+		},
+	}
+	var stdout, stderr bytes.Buffer
+	eq(t, nil, c.ExecuteWithIO(nil, strings.NewReader("world\n"), &stdout, &stderr))
+	eq(t, "Hello, world!\n", stdout.String())
+	eq(t, "Name: ", stderr.String())
+
+	// The options are restored.
+	eq(t, true, c.Opts.Writer == &original)
+	eq(t, nil, c.Opts.Reader)
+	eq(t, "", original.String())
+}
+
+func Test_ExecuteResult(t *testing.T) {
+	type summary struct {
+		Deployed []string
+	}
+	c := cli.Command{
+		Usage: "deploy [flags] <service>...",
+		Flags: []cli.Flag{&cli.BoolFlag{Name: "fail"}},
+		Exec: func(c *cli.Context) error {
+			c.SetResult(summary{Deployed: c.Args()})
+			if fail, _ := c.GetBool("fail"); fail {
+				return errors.New("failed")
+			}
+			return nil
+		},
+	}
+
+	result, err := c.ExecuteResult([]string{"api", "web"})
+	eq(t, nil, err)
+	eq(t, summary{Deployed: []string{"api", "web"}}, result)
+
+	result, err = c.ExecuteResult([]string{"--fail", "api"})
+	eq(t, "failed", fmt.Sprint(err))
+	eq(t, summary{Deployed: []string{"api"}}, result)
+
+	result, err = c.ExecuteResult([]string{"--bogus"})
+	eq(t, true, err != nil)
+	eq(t, nil, result)
+}
@@ -0,0 +1,40 @@
+// Package clitest provides helpers for end-to-end testing of CLIs built with the cli package.
+//
+// Main adapts a Command so that it can be registered as a command in rogpeppe/go-internal/testscript, which
+// allows the behavior of a CLI (args, env, stdin and expected output) to be specified in .txtar scripts:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testscript.RunMain(m, map[string]func() int{
+//			"printer": clitest.Main(newPrinterCommand),
+//		}))
+//	}
+//
+//	func TestScript(t *testing.T) {
+//		testscript.Run(t, testscript.Params{Dir: "testdata"})
+//	}
+package clitest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Main returns a function that builds a Command using newCmd and executes it with the arguments in os.Args. Errors
+// are written to the ErrWriter of the command (or os.Stderr), and the returned function reports the exit code of the
+// invocation (see cli.ExitCode).
+func Main(newCmd func() *cli.Command) func() int {
+	return func() int {
+		cmd := newCmd()
+		err := cmd.Execute(os.Args[1:])
+		if err != nil {
+			w := cmd.Opts.ErrWriter
+			if w == nil {
+				w = os.Stderr // The options are not completed if the command is misconfigured.
+			}
+			fmt.Fprintln(w, err)
+		}
+		return cli.ExitCode(err)
+	}
+}
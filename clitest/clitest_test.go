@@ -0,0 +1,72 @@
+package clitest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/clitest"
+	"github.com/rogpeppe/go-internal/testscript"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testscript.RunMain(m, map[string]func() int{
+		"printer": clitest.Main(newPrinterCommand),
+		"broken":  clitest.Main(func() *cli.Command { return &cli.Command{} }),
+	}))
+}
+
+func TestScript(t *testing.T) {
+	testscript.Run(t, testscript.Params{Dir: "testdata"})
+}
+
+func newPrinterCommand() *cli.Command {
+	return &cli.Command{
+		Usage: "printer [flags] [command]",
+		Subcommands: []*cli.Command{
+			{
+				Usage: "echo [<arg>...]",
+				Help:  "Echo the specified args",
+				Exec: func(c *cli.Context) error {
+					fmt.Println(strings.Join(c.Args(), " "))
+					return nil
+				},
+			},
+			{
+				Usage: "cat",
+				Help:  "Print stdin",
+				Exec: func(c *cli.Context) error {
+					b, err := ioutil.ReadAll(os.Stdin)
+					if err != nil {
+						return err
+					}
+					fmt.Print(string(b))
+					return nil
+				},
+			},
+			{
+				Usage: "greet [flags]",
+				Help:  "Greet someone",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name, n",
+						Usage:    "Name of the person to greet",
+						EnvVar:   []string{"GREET_NAME"},
+						Required: true,
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					name, err := c.GetString("name")
+					if err != nil {
+						return err
+					}
+					fmt.Printf("hello, %s\n", name)
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"math/rand"
+	"time"
+)
+
+// now returns the current time (see Options.Now).
+func (opts *Options) now() time.Time {
+	if opts.Now != nil {
+		return opts.Now()
+	}
+	return time.Now()
+}
+
+// int63n returns a random number in [0, n) (see Options.Rand).
+func (opts *Options) int63n(n int64) int64 {
+	if opts.Rand != nil {
+		return opts.Rand.Int63n(n)
+	}
+	return rand.Int63n(n)
+}
@@ -0,0 +1,53 @@
+package cli
+
+// Clone returns a deep copy of the command tree, including copies of the flags and subcommands, so that the copy can
+// be modified (e.g. to set different Options) and executed independently of the original. Note that functions (such
+// as Exec) and the fields of Options are not copied.
+func (c *Command) Clone() *Command {
+	clone := &Command{}
+	*clone = *c
+	clone.fs, clone.sources, clone.parent, clone.builtins, clone.groups = nil, nil, nil, nil, nil
+
+	clone.required, clone.hidden = copyBoolMap(c.required), copyBoolMap(c.hidden)
+
+	clone.Flags = copyFlags(c.Flags)
+	clone.FlagGroups = nil
+	for _, group := range c.FlagGroups {
+		clone.FlagGroups = append(clone.FlagGroups, FlagGroup{Name: group.Name, Flags: copyFlags(group.Flags)})
+	}
+	if c.Annotations != nil {
+		clone.Annotations = make(map[string]string, len(c.Annotations))
+		for k, v := range c.Annotations {
+			clone.Annotations[k] = v
+		}
+	}
+	clone.Subcommands = nil
+	for _, subcommand := range c.Subcommands {
+		clone.Subcommands = append(clone.Subcommands, subcommand.Clone())
+	}
+	return clone
+}
+
+// copyFlags returns copies of the flags (see copyFlag).
+func copyFlags(flags []Flag) []Flag {
+	if flags == nil {
+		return nil
+	}
+	copies := make([]Flag, len(flags))
+	for i, f := range flags {
+		copies[i] = copyFlag(f)
+	}
+	return copies
+}
+
+// copyBoolMap returns a copy of the map.
+func copyBoolMap(m map[string]bool) map[string]bool {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,55 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestClone(t *testing.T) {
+	original := &cli.Command{
+		Usage: "printer [command]",
+		Flags: []cli.Flag{&cli.StringSliceFlag{Name: "tag", Value: []string{"a"}}},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "echo",
+				Exec: func(c *cli.Context) error {
+					tags, err := c.GetStringSlice("tag")
+					if err != nil {
+						return err
+					}
+					return c.Print(strings.Join(tags, ","))
+				},
+			},
+		},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		outputs = make([]bytes.Buffer, 10)
+	)
+	for i := range outputs {
+		clone := original.Clone()
+		clone.Opts.Writer = &outputs[i]
+		clone.Flags[0].(*cli.StringSliceFlag).Value = append(clone.Flags[0].(*cli.StringSliceFlag).Value, fmt.Sprint(i))
+
+		wg.Add(1)
+		go func(c *cli.Command) {
+			defer wg.Done()
+			if err := c.Execute([]string{"echo"}); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}(clone)
+	}
+	wg.Wait()
+
+	for i := range outputs {
+		eq(t, fmt.Sprintf("a,%d\n", i), outputs[i].String())
+	}
+	eq(t, []string{"a"}, original.Flags[0].(*cli.StringSliceFlag).Value)
+	eq(t, nil, original.Opts.Writer)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ColorMode decides when the color-aware printing helpers use colors.
+type ColorMode int
+
+const (
+	// ColorAuto uses colors when writing to a terminal, unless NO_COLOR is set. Setting CLICOLOR_FORCE (to anything
+	// other than 0) enables colors even when not writing to a terminal.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways always uses colors.
+	ColorAlways
+
+	// ColorNever never uses colors.
+	ColorNever
+)
+
+// ANSI escape codes used by the color-aware printing helpers.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// Successf writes a line to the configured Writer, in green if colors are enabled.
+func (c *Context) Successf(format string, a ...interface{}) {
+	c.colorf(c.cmd.options().Writer, colorGreen, format, a...)
+}
+
+// Warnf writes a line to the configured ErrWriter, in yellow if colors are enabled.
+func (c *Context) Warnf(format string, a ...interface{}) {
+	c.colorf(c.cmd.options().ErrWriter, colorYellow, format, a...)
+}
+
+// Errorf writes a line to the configured ErrWriter, in red if colors are enabled.
+func (c *Context) Errorf(format string, a ...interface{}) {
+	c.colorf(c.cmd.options().ErrWriter, colorRed, format, a...)
+}
+
+// colorf writes the formatted line to w, wrapped in the color if colors are enabled for w.
+func (c *Context) colorf(w io.Writer, color, format string, a ...interface{}) {
+	msg := strings.TrimSuffix(fmt.Sprintf(format, a...), "\n")
+	if useColor(c.cmd.options(), w) {
+		msg = color + msg + colorReset
+	}
+	fmt.Fprintln(w, msg)
+}
+
+// useColor returns true if colors should be used when writing to w using the given ColorMode.
+func useColor(opts *Options, w io.Writer) bool {
+	switch opts.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if v, ok := opts.lookupEnv("NO_COLOR"); ok && v != "" {
+		return false
+	}
+	if v, ok := opts.lookupEnv("CLICOLOR_FORCE"); ok && v != "" && v != "0" {
+		return true
+	}
+	return isTerminal(w)
+}
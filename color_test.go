@@ -0,0 +1,75 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestColorPrinting(t *testing.T) {
+	tests := []struct {
+		description    string
+		color          cli.ColorMode
+		env            map[string]string
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			description:    "no colors when not a terminal",
+			expectedStdout: "created 3 instances\n",
+			expectedStderr: "instance i-1 is slow\nfailed to create i-2\n",
+		},
+		{
+			description:    "colors when forced",
+			env:            map[string]string{"CLICOLOR_FORCE": "1"},
+			expectedStdout: "\x1b[32mcreated 3 instances\x1b[0m\n",
+			expectedStderr: "\x1b[33minstance i-1 is slow\x1b[0m\n\x1b[31mfailed to create i-2\x1b[0m\n",
+		},
+		{
+			description:    "NO_COLOR takes precedence",
+			env:            map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"},
+			expectedStdout: "created 3 instances\n",
+			expectedStderr: "instance i-1 is slow\nfailed to create i-2\n",
+		},
+		{
+			description:    "options override the environment",
+			color:          cli.ColorAlways,
+			env:            map[string]string{"NO_COLOR": "1"},
+			expectedStdout: "\x1b[32mcreated 3 instances\x1b[0m\n",
+			expectedStderr: "\x1b[33minstance i-1 is slow\x1b[0m\n\x1b[31mfailed to create i-2\x1b[0m\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			c := cli.Command{
+				Usage: "create",
+				Exec: func(c *cli.Context) error {
+					c.Successf("created %d instances", 3)
+					c.Warnf("instance %s is slow", "i-1")
+					c.Errorf("failed to create %s\n", "i-2")
+					return nil
+				},
+				Opts: cli.Options{
+					Writer:    &stdout,
+					ErrWriter: &stderr,
+					Color:     tc.color,
+				},
+			}
+
+			for k, v := range tc.env {
+				if err := os.Setenv(k, v); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Unsetenv(k)
+			}
+
+			eq(t, nil, c.Execute(nil))
+			eq(t, tc.expectedStdout, stdout.String())
+			eq(t, tc.expectedStderr, stderr.String())
+		})
+	}
+}
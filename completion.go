@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// completionShells are the shells that completion scripts can be generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// GenCompletion writes a completion script for the shell ("bash", "zsh" or "fish") to w. The script calls the hidden
+// "__complete" command of the application to get the candidates (see Options.CompletionCommand), which completes the
+// names of the subcommands, the flags and the ValidArgs of the (visible) commands in the tree. Files are completed
+// when there are no candidates.
+func GenCompletion(c *Command, shell string, w io.Writer) error {
+	var (
+		b   strings.Builder
+		app = c.name()
+		fn  = completionFuncName(app)
+	)
+	switch shell {
+	case "bash":
+		fmt.Fprintf(&b, "# bash completion for %s.\n", app)
+		fmt.Fprintf(&b, "%s() {\n", fn)
+		fmt.Fprintf(&b, "\tlocal IFS=$'\\n'\n")
+		fmt.Fprintf(&b, "\tCOMPREPLY=($(\"${COMP_WORDS[0]}\" %s \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null))\n", completeCommandName)
+		fmt.Fprintf(&b, "}\n")
+		fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, app)
+	case "zsh":
+		fmt.Fprintf(&b, "#compdef %s\n", app)
+		fmt.Fprintf(&b, "# zsh completion for %s.\n", app)
+		fmt.Fprintf(&b, "%s() {\n", fn)
+		fmt.Fprintf(&b, "\tlocal -a candidates\n")
+		fmt.Fprintf(&b, "\tcandidates=(${(f)\"$(\"${words[1]}\" %s \"${(@)words[2,CURRENT]}\" 2>/dev/null)\"})\n", completeCommandName)
+		fmt.Fprintf(&b, "\tcompadd -- \"${candidates[@]}\" || _files\n")
+		fmt.Fprintf(&b, "}\n")
+		// The script is either autoloaded from the fpath (as the _app function), or sourced.
+		fmt.Fprintf(&b, "if [ \"$funcstack[1]\" = \"_%s\" ]; then\n\t%s \"$@\"\nelse\n\tcompdef %s %s\nfi\n", app, fn, fn, app)
+	case "fish":
+		fmt.Fprintf(&b, "# fish completion for %s.\n", app)
+		fmt.Fprintf(&b, "function %s\n", fn)
+		fmt.Fprintf(&b, "    set -l tokens (commandline -opc)\n")
+		fmt.Fprintf(&b, "    set -l program $tokens[1]\n")
+		fmt.Fprintf(&b, "    set -e tokens[1]\n")
+		fmt.Fprintf(&b, "    set -l current (commandline -ct)\n")
+		fmt.Fprintf(&b, "    $program %s $tokens \"$current\" 2>/dev/null\n", completeCommandName)
+		fmt.Fprintf(&b, "end\n")
+		fmt.Fprintf(&b, "complete -c %s -a '(%s)'\n", app, fn)
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(completionShells, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// completionFuncName returns the name of the shell function for the application, e.g. "_my_app_completion".
+func completionFuncName(app string) string {
+	return "_" + regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString(app, "_") + "_completion"
+}
+
+// completeCommandName is the name of the hidden command that is called by the completion scripts.
+const completeCommandName = "__complete"
+
+// completeCommand returns the hidden command used by the completion scripts: "app __complete <args>... <word>" prints
+// the candidates for the last argument (which is empty when completing a new word), one per line. The candidates are
+// also the result of the command (see Command.ExecuteResult), so that completions can be tested in Go, e.g.:
+//
+//	candidates, err := app.ExecuteResult([]string{"__complete", "deploy", "--"})
+func completeCommand() *Command {
+	return &Command{
+		Usage:           completeCommandName + " [args...]",
+		Help:            "Print the completion candidates for the last argument",
+		Hidden:          true,
+		SkipFlagParsing: true,
+		Exec: func(c *Context) error {
+			root := c.cmd.root()
+			candidates := root.completions(c.Args())
+			c.SetResult(candidates)
+			for _, candidate := range candidates {
+				fmt.Fprintln(root.options().Writer, candidate)
+			}
+			return nil
+		},
+	}
+}
+
+// completions returns the candidates for completing the last of the arguments. Subcommands and ValidArgs are
+// completed for positional arguments, and flags when the word starts with "-". Values of flags and arguments after the
+// "--" terminator are not completed.
+func (c *Command) completions(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	word := args[len(args)-1]
+	cmd, rest, _, err := c.dispatch(args[:len(args)-1], 0)
+	if err != nil {
+		return nil
+	}
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == "--" {
+			return nil
+		}
+	}
+	if n := len(rest); n > 0 && strings.HasPrefix(rest[n-1], "-") && flagArgs(cmd.fs, []string{rest[n-1], word}) == 2 {
+		return nil // The word is the value of a flag.
+	}
+
+	var candidates []string
+	switch {
+	case strings.HasPrefix(word, "-"):
+		if strings.Contains(word, "=") {
+			return nil
+		}
+		for _, f := range cmd.visibleFlags(cmd.CombinedFlags()) {
+			candidates = append(candidates, "--"+f.GetName())
+			if s := f.GetShorthand(); s != "" {
+				candidates = append(candidates, "-"+s)
+			}
+		}
+		if help := cmd.helpFlag(); help.name != "" && cmd.fs.Lookup(help.name) == nil {
+			candidates = append(candidates, "--"+help.name)
+			if help.shorthand != "" {
+				candidates = append(candidates, "-"+help.shorthand)
+			}
+		}
+	case positionalIndex(cmd.fs, rest) < 0:
+		// Only the first positional argument is completed, since later ones follow an unknown subcommand (or a
+		// ValidArgs value).
+		for _, s := range cmd.visibleSubcommands() {
+			candidates = append(candidates, s.name())
+		}
+		candidates = append(candidates, cmd.ValidArgs...)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// completionScriptPath returns the conventional location of a user-installed completion script for the shell.
+func (opts *Options) completionScriptPath(shell, app string) (string, error) {
+	home := opts.getenv("HOME")
+	if home == "" {
+		var err error
+		if home, err = opts.userHomeDir(); err != nil {
+			return "", err
+		}
+	}
+	switch shell {
+	case "bash":
+		dir := opts.getenv("XDG_DATA_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dir, "bash-completion", "completions", app), nil
+	case "zsh":
+		return filepath.Join(home, ".zsh", "completions", "_"+app), nil
+	case "fish":
+		dir := opts.getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(dir, "fish", "completions", app+".fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(completionShells, ", "))
+	}
+}
+
+// completionCommand returns the builtin completion command (see Options.CompletionCommand).
+func completionCommand() *Command {
+	cmd := &Command{
+		Usage: "completion [command]",
+		Help:  "Generate or install shell completion scripts",
+	}
+	for _, shell := range completionShells {
+		shell := shell
+		cmd.Subcommands = append(cmd.Subcommands, &Command{
+			Usage: shell,
+			Help:  fmt.Sprintf("Print the completion script for %s", shell),
+			Exec: func(c *Context) error {
+				root := c.cmd.root()
+				return GenCompletion(root, shell, root.options().Writer)
+			},
+		})
+	}
+	cmd.Subcommands = append(cmd.Subcommands, &Command{
+		Usage: "install [flags]",
+		Help:  "Install the completion script for the current shell (detected from $SHELL)",
+		Flags: []Flag{
+			&StringFlag{Name: "shell", Usage: "Shell to install the completion script for (bash, zsh or fish)"},
+			&BoolFlag{Name: "dry-run", Usage: "Print where the completion script would be installed, without writing it"},
+		},
+		Exec: func(c *Context) error {
+			var (
+				root      = c.cmd.root()
+				opts      = root.options()
+				shell, _  = c.GetString("shell")
+				dryRun, _ = c.GetBool("dry-run")
+			)
+			if shell == "" {
+				shell = filepath.Base(opts.getenv("SHELL"))
+			}
+			if shell == "" || shell == "." {
+				return errors.New("unable to detect the shell, use --shell")
+			}
+			path, err := opts.completionScriptPath(shell, root.name())
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				_, err := fmt.Fprintf(opts.Writer, "Would install the %s completion script to %s\n", shell, path)
+				return err
+			}
+			var b strings.Builder
+			if err := GenCompletion(root, shell, &b); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return fmt.Errorf("installing completion script: %w", err)
+			}
+			if err := ioutil.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+				return fmt.Errorf("installing completion script: %w", err)
+			}
+			fmt.Fprintf(opts.Writer, "Installed the %s completion script to %s\n", shell, path)
+			if shell == "zsh" {
+				fmt.Fprintf(opts.Writer, "Make sure that %s is in your fpath (before compinit is called in ~/.zshrc)\n", filepath.Dir(path))
+			}
+			return nil
+		},
+	})
+	return cmd
+}
@@ -0,0 +1,181 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func newCompletionCommand(opts cli.Options) *cli.Command {
+	opts.CompletionCommand = true
+	return &cli.Command{
+		Usage: "app [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region, r"}},
+		Opts:  opts,
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [command]",
+				Flags: []cli.Flag{&cli.BoolFlag{Name: "force"}},
+				Subcommands: []*cli.Command{
+					{
+						Usage:     "service <name>",
+						ValidArgs: []string{"api", "web"},
+						Exec:      func(c *cli.Context) error { return nil },
+					},
+				},
+			},
+			{
+				Usage:  "debug",
+				Hidden: true,
+				Exec:   func(c *cli.Context) error { return nil },
+			},
+		},
+	}
+}
+
+func TestGenCompletion_Bash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	var script bytes.Buffer
+	if err := cli.GenCompletion(newCompletionCommand(cli.Options{}), "bash", &script); err != nil {
+		t.Fatal(err)
+	}
+
+	// The application is replaced by a function which prints its arguments as the candidates.
+	test := script.String() + `
+app() { printf '%s\n' "$@"; }
+COMP_WORDS=(app --region "eu west" de)
+COMP_CWORD=3
+_app_completion
+printf '%s\n' "${COMPREPLY[@]}"
+`
+	out, err := exec.Command(bash, "--norc", "-c", test).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %s", err, out)
+	}
+	eq(t, "__complete\n--region\neu west\nde\n", string(out))
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{""}, expected: []string{"deploy", "completion"}},
+		{args: []string{"d"}, expected: []string{"deploy"}},
+		{args: []string{"deploy", ""}, expected: []string{"service"}},
+		{args: []string{"deploy", "-"}, expected: []string{"--force", "--region", "-r", "--help", "-h"}},
+		{args: []string{"deploy", "--f"}, expected: []string{"--force"}},
+		{args: []string{"--region", "eu", "deploy", "service", ""}, expected: []string{"api", "web"}},
+		{args: []string{"deploy", "service", "w"}, expected: []string{"web"}},
+		{args: []string{"deploy", "service", "api", ""}},
+		{args: []string{"deploy", "--region", ""}},
+		{args: []string{"deploy", "--region=e"}},
+		{args: []string{"deploy", "--", ""}},
+		{args: []string{"completion", "install", "--d"}, expected: []string{"--dry-run"}},
+		{args: []string{"bogus", ""}},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			var out bytes.Buffer
+			c := newCompletionCommand(cli.Options{Writer: &out})
+			result, err := c.ExecuteResult(append([]string{"__complete"}, tc.args...))
+			eq(t, nil, err)
+			eq(t, tc.expected, result)
+
+			var lines []string
+			for _, candidate := range tc.expected {
+				lines = append(lines, candidate+"\n")
+			}
+			eq(t, strings.Join(lines, ""), out.String())
+		})
+	}
+}
+
+func TestGenCompletion_UnsupportedShell(t *testing.T) {
+	err := cli.GenCompletion(newCompletionCommand(cli.Options{}), "powershell", ioutil.Discard)
+	eq(t, `unsupported shell "powershell" (supported: bash, zsh, fish)`, fmt.Sprint(err))
+}
+
+func TestCompletionInstall(t *testing.T) {
+	home := t.TempDir()
+
+	tests := []struct {
+		description    string
+		args           []string
+		environ        []string
+		expectedPath   string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			description:    "bash from SHELL",
+			args:           []string{"completion", "install"},
+			environ:        []string{"HOME=" + home, "SHELL=/bin/bash"},
+			expectedPath:   filepath.Join(home, ".local", "share", "bash-completion", "completions", "app"),
+			expectedOutput: "Installed the bash completion script to %s\n",
+		},
+		{
+			description:  "zsh",
+			args:         []string{"completion", "install", "--shell", "zsh"},
+			environ:      []string{"HOME=" + home, "SHELL=/bin/bash"},
+			expectedPath: filepath.Join(home, ".zsh", "completions", "_app"),
+			expectedOutput: "Installed the zsh completion script to %s\n" +
+				"Make sure that " + filepath.Join(home, ".zsh", "completions") + " is in your fpath (before compinit is called in ~/.zshrc)\n",
+		},
+		{
+			description:    "fish with XDG_CONFIG_HOME",
+			args:           []string{"completion", "install"},
+			environ:        []string{"HOME=" + home, "SHELL=/usr/bin/fish", "XDG_CONFIG_HOME=" + filepath.Join(home, "xdg")},
+			expectedPath:   filepath.Join(home, "xdg", "fish", "completions", "app.fish"),
+			expectedOutput: "Installed the fish completion script to %s\n",
+		},
+		{
+			description:    "dry run",
+			args:           []string{"completion", "install", "--dry-run"},
+			environ:        []string{"HOME=" + filepath.Join(home, "dry-run"), "SHELL=/bin/bash"},
+			expectedOutput: "Would install the bash completion script to " + filepath.Join(home, "dry-run", ".local", "share", "bash-completion", "completions", "app") + "\n",
+		},
+		{
+			description: "unknown shell",
+			args:        []string{"completion", "install"},
+			environ:     []string{"HOME=" + home},
+			expectedErr: "unable to detect the shell, use --shell",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var out bytes.Buffer
+			c := newCompletionCommand(cli.Options{Environ: tc.environ, Writer: &out})
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				eq(t, tc.expectedErr, fmt.Sprint(err))
+				return
+			}
+			eq(t, nil, err)
+
+			if tc.expectedPath == "" {
+				eq(t, tc.expectedOutput, out.String())
+				return
+			}
+			eq(t, fmt.Sprintf(tc.expectedOutput, tc.expectedPath), out.String())
+			b, err := ioutil.ReadFile(tc.expectedPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			eq(t, true, strings.Contains(string(b), "_app_completion"))
+		})
+	}
+
+	_, err := ioutil.ReadDir(filepath.Join(home, "dry-run"))
+	eq(t, true, err != nil)
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileResolver implements FlagResolver by resolving flags from a YAML config file, where the keys are the names
+// of the flags and the values are scalars (or sequences, for slice flags). E.g.:
+//
+//	region: eu-west-1
+//	instance: [i-123, i-456]
+//
+// Use NewConfigFileResolver to create the resolver.
+type ConfigFileResolver struct {
+	path   string
+	values map[string]*yaml.Node
+}
+
+// NewConfigFileResolver reads the config file at path and returns a ConfigFileResolver for it. The resolver does not
+// resolve any flags if the file does not exist.
+func NewConfigFileResolver(path string) (*ConfigFileResolver, error) {
+	r := &ConfigFileResolver{path: path, values: make(map[string]*yaml.Node)}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config file %q: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return r, nil // Empty (or only comments).
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing config file %q: expected a mapping of flag names to values", path)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+		case yaml.SequenceNode:
+			for _, n := range value.Content {
+				if n.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("parsing config file %q: %s: expected a sequence of scalars", path, key)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("parsing config file %q: %s: expected a scalar or sequence", path, key)
+		}
+		r.values[key] = value
+	}
+	return r, nil
+}
+
+// Resolve implements FlagResolver.
+func (r *ConfigFileResolver) Resolve(flag Flag) (string, bool) {
+	n, ok := r.values[flag.GetName()]
+	if !ok {
+		return "", false
+	}
+	if n.Kind == yaml.ScalarNode {
+		return n.Value, true
+	}
+	var values []string
+	for _, v := range n.Content {
+		values = append(values, v.Value)
+	}
+	return joinSliceValues(flag, values), true
+}
+
+// Name implements NamedResolver.
+func (*ConfigFileResolver) Name() string {
+	return "config"
+}
+
+// Source implements FlagSource.
+func (r *ConfigFileResolver) Source(flag Flag) string {
+	return fmt.Sprintf("%s (%s)", flag.GetName(), r.path)
+}
+
+// joinSliceValues joins the values so that they are split into the same values when the resolved value is set, i.e.
+// by escaping the delimiter of the flag (see SliceFlag.GetDelimiter) or by using CSV (which is how pflag splits slice
+// values by default).
+func joinSliceValues(flag Flag, values []string) string {
+	if sf, ok := flag.(SliceFlag); ok && sf.GetDelimiter() != "" {
+		d := sf.GetDelimiter()
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			v = strings.ReplaceAll(v, `\`, `\\`)
+			escaped[i] = strings.ReplaceAll(v, d, `\`+d)
+		}
+		return strings.Join(escaped, d)
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(values)
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// GenConfigSkeleton writes a config file (for ConfigFileResolver) to w, which contains every (visible) flag in the
+// command tree with its description, type and default value. The flags are commented out, so that the file does not
+// change any values until it is edited.
+func GenConfigSkeleton(c *Command, w io.Writer) error {
+	if err := c.initializeTree(); err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Configuration for %s.\n", c.name())
+	genConfigSkeleton(c, &b, make(map[string]bool))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// genConfigSkeleton writes the flags of an initialized command tree, skipping flags that have already been written.
+func genConfigSkeleton(c *Command, b *strings.Builder, seen map[string]bool) {
+	fs := c.flagSet(c.LocalFlags())
+	for _, f := range c.visibleFlags(c.LocalFlags()) {
+		if seen[f.GetName()] || f.GetName() == versionFlagName {
+			continue
+		}
+		seen[f.GetName()] = true
+
+		pf := fs.Lookup(f.GetName())
+		def := pf.DefValue
+		if pf.Value.Type() == "string" {
+			def = strconv.Quote(def)
+		}
+		fmt.Fprintln(b)
+		if usage := f.GetUsage(); usage != "" {
+			fmt.Fprintf(b, "# %s\n", usage)
+		}
+		fmt.Fprintf(b, "# Type: %s", pf.Value.Type())
+		if c.isRequired(f) {
+			fmt.Fprint(b, " (required)")
+		}
+		fmt.Fprintf(b, "\n#%s: %s\n", f.GetName(), def)
+	}
+	for _, subcommand := range c.visibleSubcommands() {
+		genConfigSkeleton(subcommand, b, seen)
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ConfigDirs returns the directories that are searched for the config files of the app, in order of precedence:
+//
+//  1. $XDG_CONFIG_HOME/<app> (on all platforms, if set).
+//  2. The user config directory of the platform: ~/.config/<app> on Linux and other Unix systems,
+//     ~/Library/Application Support/<app> followed by ~/.config/<app> on macOS, and %APPDATA%\<app> on Windows.
+//  3. The system config directories on Linux and other Unix systems: <dir>/<app> for each directory in
+//     $XDG_CONFIG_DIRS (which defaults to /etc/xdg).
+func ConfigDirs(app string) []string {
+	return (&Options{}).configDirs(app)
+}
+
+// configDirs implements ConfigDirs using the environment of the application (see Options.Environ).
+func (opts *Options) configDirs(app string) []string {
+	var (
+		dirs []string
+		seen = make(map[string]bool)
+	)
+	add := func(parts ...string) {
+		if parts[0] == "" {
+			return
+		}
+		dir := filepath.Join(append(parts, app)...)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	add(opts.getenv("XDG_CONFIG_HOME"))
+
+	home, _ := opts.userHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		add(opts.getenv("APPDATA"))
+	case "darwin":
+		add(home, "Library", "Application Support")
+		add(home, ".config")
+	default:
+		add(home, ".config")
+		systemDirs := opts.getenv("XDG_CONFIG_DIRS")
+		if systemDirs == "" {
+			systemDirs = "/etc/xdg"
+		}
+		for _, dir := range filepath.SplitList(systemDirs) {
+			add(dir)
+		}
+	}
+	return dirs
+}
+
+// FindConfigFile returns the path of the first config file of the app that exists, searching for each of the names
+// in each of the ConfigDirs (i.e. the directories take precedence over the names). False is returned if none of the
+// files exist.
+func FindConfigFile(app string, names ...string) (string, bool) {
+	return (&Options{}).findConfigFile(app, names...)
+}
+
+// findConfigFile implements FindConfigFile using the environment of the application.
+func (opts *Options) findConfigFile(app string, names ...string) (string, bool) {
+	for _, dir := range opts.configDirs(app) {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// NewAppConfigResolver returns a ConfigFileResolver for the config file of the app, which is the first config.yaml
+// (or config.yml) that exists in the ConfigDirs. If there is no config file, the resolver uses config.yaml in the
+// first of the ConfigDirs (and does not resolve any flags).
+func NewAppConfigResolver(app string) (*ConfigFileResolver, error) {
+	return NewConfigFileResolver(AppConfigPath(app))
+}
+
+// AppConfigPath returns the path of the config file of the app that is used by NewAppConfigResolver, which might not
+// exist.
+func AppConfigPath(app string) string {
+	return (&Options{}).appConfigPath(app)
+}
+
+// appConfigPath implements AppConfigPath using the environment of the application.
+func (opts *Options) appConfigPath(app string) string {
+	if path, found := opts.findConfigFile(app, "config.yaml", "config.yml"); found {
+		return path
+	}
+	if dirs := opts.configDirs(app); len(dirs) > 0 {
+		return filepath.Join(dirs[0], "config.yaml")
+	}
+	return ""
+}
+
+// FindProjectConfigFile returns the path of the first project config file (e.g. ".mycli.yaml") that exists, searching
+// for each of the names in dir and each of its parents up to the root of the git repository (i.e. the first directory
+// that contains .git) or the root of the filesystem. False is returned if none of the files exist.
+func FindProjectConfigFile(dir string, names ...string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", false // Root of the repository.
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// NewProjectConfigResolver returns a resolver for the project config file of the app (see FindProjectConfigFile,
+// starting from the working directory), which is merged beneath the user config file (see NewAppConfigResolver), i.e.
+// values in the user config take precedence over the project config. The user config is used alone if there is no
+// project config file.
+func NewProjectConfigResolver(app string, names ...string) (FlagResolver, error) {
+	user, err := NewAppConfigResolver(app)
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return user, nil
+	}
+	path, found := FindProjectConfigFile(wd, names...)
+	if !found {
+		return user, nil
+	}
+	project, err := NewConfigFileResolver(path)
+	if err != nil {
+		return nil, err
+	}
+	return Override(project, user), nil
+}
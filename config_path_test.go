@@ -0,0 +1,130 @@
+package cli_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestConfigDirs(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the test uses the search order on Linux")
+	}
+	var (
+		tmp    = t.TempDir()
+		home   = filepath.Join(tmp, "home")
+		xdg    = filepath.Join(tmp, "xdg")
+		system = filepath.Join(tmp, "etc")
+	)
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("XDG_CONFIG_DIRS", system+string(os.PathListSeparator)+filepath.Join(tmp, "opt"))
+
+	eq(t, []string{
+		filepath.Join(xdg, "deployer"),
+		filepath.Join(home, ".config", "deployer"),
+		filepath.Join(system, "deployer"),
+		filepath.Join(tmp, "opt", "deployer"),
+	}, cli.ConfigDirs("deployer"))
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		t.Setenv("XDG_CONFIG_DIRS", "")
+		eq(t, []string{filepath.Join(home, ".config", "deployer"), "/etc/xdg/deployer"}, cli.ConfigDirs("deployer"))
+	})
+
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("find", func(t *testing.T) {
+		_, found := cli.FindConfigFile("deployer", "config.yaml")
+		eq(t, false, found)
+
+		write(filepath.Join(system, "deployer", "config.yaml"), "region: eu-north-1\n")
+		write(filepath.Join(home, ".config", "deployer", "config.yml"), "region: eu-west-1\n")
+		path, found := cli.FindConfigFile("deployer", "config.yaml", "config.yml")
+		eq(t, filepath.Join(home, ".config", "deployer", "config.yml"), path)
+		eq(t, true, found)
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		resolver, err := cli.NewAppConfigResolver("deployer")
+		eq(t, nil, err)
+		region, found := resolver.Resolve(&cli.StringFlag{Name: "region"})
+		eq(t, "eu-west-1", region)
+		eq(t, true, found)
+
+		resolver, err = cli.NewAppConfigResolver("other")
+		eq(t, nil, err)
+		eq(t, "region ("+filepath.Join(xdg, "other", "config.yaml")+")", resolver.Source(&cli.StringFlag{Name: "region"}))
+	})
+}
+
+func TestProjectConfig(t *testing.T) {
+	var (
+		tmp     = t.TempDir()
+		repo    = filepath.Join(tmp, "repo")
+		nested  = filepath.Join(repo, "services", "api")
+		xdgHome = filepath.Join(tmp, "xdg")
+	)
+	t.Setenv("XDG_CONFIG_HOME", xdgHome)
+	for path, content := range map[string]string{
+		filepath.Join(tmp, ".deployer.yaml"):                 "region: ap-south-1\n",
+		filepath.Join(repo, ".git", "HEAD"):                  "ref: refs/heads/main\n",
+		filepath.Join(repo, ".deployer.yaml"):                "region: eu-west-1\ntimeout: 30s\n",
+		filepath.Join(xdgHome, "deployer", "config.yaml"):    "region: us-east-1\nreplicas: 3\n",
+		filepath.Join(nested, "README.md"):                   "",
+		filepath.Join(tmp, "outside", "nested", "README.md"): "",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("find", func(t *testing.T) {
+		path, found := cli.FindProjectConfigFile(nested, ".deployer.yml", ".deployer.yaml")
+		eq(t, filepath.Join(repo, ".deployer.yaml"), path)
+		eq(t, true, found)
+
+		path, found = cli.FindProjectConfigFile(filepath.Join(tmp, "outside", "nested"), ".deployer.yaml")
+		eq(t, filepath.Join(tmp, ".deployer.yaml"), path)
+		eq(t, true, found)
+
+		// The search stops at the root of the repository.
+		_, found = cli.FindProjectConfigFile(nested, ".other.yaml")
+		eq(t, false, found)
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		wd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chdir(wd)
+		if err := os.Chdir(nested); err != nil {
+			t.Fatal(err)
+		}
+		resolver, err := cli.NewProjectConfigResolver("deployer", ".deployer.yaml")
+		eq(t, nil, err)
+		// The user config takes precedence over the project config.
+		region, _ := resolver.Resolve(&cli.StringFlag{Name: "region"})
+		eq(t, "us-east-1", region)
+		timeout, _ := resolver.Resolve(&cli.DurationFlag{Name: "timeout"})
+		eq(t, "30s", timeout)
+		replicas, _ := resolver.Resolve(&cli.IntFlag{Name: "replicas"})
+		eq(t, "3", replicas)
+	})
+}
@@ -0,0 +1,103 @@
+package cli_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func newConfigCommand() *cli.Command {
+	return &cli.Command{
+		Usage: "deployer [command]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region", Usage: "AWS region to target", Value: "eu-west-1"},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "replicas", Usage: "Number of replicas", Value: 3},
+					&cli.StringSliceFlag{Name: "tag", Usage: "Tags to apply", Required: true},
+					&cli.StringSliceFlag{Name: "path", Delimiter: ":"},
+				},
+				Exec: func(c *cli.Context) error { return nil },
+			},
+		},
+	}
+}
+
+func TestConfigFileResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := `# Comments are ignored.
+region: us-east-1
+replicas: 5
+tag: [a, "b,c"]
+path: ["/usr/bin", "C:\\bin:x"]
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := cli.NewConfigFileResolver(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c := newConfigCommand()
+	c.Opts.Resolvers = []cli.FlagResolver{resolver}
+	c.Subcommands[0].Exec = func(c *cli.Context) error {
+		region, _ := c.GetString("region")
+		eq(t, "us-east-1", region)
+		eq(t, "region ("+path+")", c.FlagSource("region"))
+		replicas, _ := c.GetInt("replicas")
+		eq(t, 5, replicas)
+		tags, _ := c.GetStringSlice("tag")
+		eq(t, []string{"a", "b,c"}, tags)
+		paths, _ := c.GetStringSlice("path")
+		eq(t, []string{"/usr/bin", `C:\bin:x`}, paths)
+		return nil
+	}
+	eq(t, nil, c.Execute([]string{"deploy"}))
+
+	t.Run("missing file", func(t *testing.T) {
+		resolver, err := cli.NewConfigFileResolver(filepath.Join(t.TempDir(), "missing.yaml"))
+		eq(t, nil, err)
+		_, found := resolver.Resolve(&cli.StringFlag{Name: "region"})
+		eq(t, false, found)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := ioutil.WriteFile(path, []byte("region:\n  nested: true\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := cli.NewConfigFileResolver(path)
+		eq(t, `parsing config file "`+path+`": region: expected a scalar or sequence`, err.Error())
+	})
+}
+
+func TestGenConfigSkeleton(t *testing.T) {
+	var b bytes.Buffer
+	eq(t, nil, cli.GenConfigSkeleton(newConfigCommand(), &b))
+
+	expected := `# Configuration for deployer.
+
+# AWS region to target
+# Type: string
+#region: "eu-west-1"
+
+# Number of replicas
+# Type: int
+#replicas: 3
+
+# Tags to apply
+# Type: stringSlice (required)
+#tag: []
+
+# Type: stringSlice
+#path: []
+`
+	eq(t, expected, b.String())
+}
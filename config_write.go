@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetConfigValue sets the value of a flag in the YAML config file at path (see ConfigFileResolver), creating the file
+// (readable only by the user, since it can contain secrets) if it does not exist. Comments and the order of the keys
+// in the file are preserved (blank lines are not).
+func SetConfigValue(path, name, value string) error {
+	return editConfigFile(path, name, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+// SetConfigValues sets the values of a (slice) flag in the YAML config file at path, see SetConfigValue.
+func SetConfigValues(path, name string, values []string) error {
+	n := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, v := range values {
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+	}
+	return editConfigFile(path, name, n)
+}
+
+// UnsetConfigValue removes a flag from the YAML config file at path. It is not an error if the file does not exist, or
+// if the flag is not set in it.
+func UnsetConfigValue(path, name string) error {
+	return editConfigFile(path, name, nil)
+}
+
+// editConfigFile sets the value of the flag in the config file to the node, or removes it if the node is nil.
+func editConfigFile(path, name string, value *yaml.Node) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("parsing config file %q: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		if value == nil {
+			return nil
+		}
+		// Files that are empty (or only contain comments) are parsed as an empty document.
+		doc = yaml.Node{
+			Kind:        yaml.DocumentNode,
+			HeadComment: strings.TrimSpace(string(b)),
+			Content:     []*yaml.Node{{Kind: yaml.MappingNode}},
+		}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing config file %q: expected a mapping of flag names to values", path)
+	}
+
+	set := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != name {
+			continue
+		}
+		if value == nil {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		} else {
+			old := root.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			root.Content[i+1] = value
+		}
+		set = true
+		break
+	}
+	if !set {
+		if value == nil {
+			return nil
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	// The mode is only used when the file is created, existing files keep their mode.
+	if err := ioutil.WriteFile(path, out.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
+}
+
+// configCommand returns the config subcommand, which edits the values of flags in the config file of the application
+// (see AppConfigPath).
+func configCommand() *Command {
+	return &Command{
+		Usage: "config [command]",
+		Help:  "Manage the default flag values in the config file",
+		Subcommands: []*Command{
+			{
+				Usage: "set <flag> <value>...",
+				Help:  "Set the default value of a flag (multiple values can be given for slice flags)",
+				Exec: func(c *Context) error {
+					if len(c.Args()) < 2 {
+						return errors.New("expected the name of a flag and a value")
+					}
+					root := c.cmd.root()
+					flag, err := root.lookupTreeFlag(c.Args()[0])
+					if err != nil {
+						return err
+					}
+					values := c.Args()[1:]
+					fs := newFS([]Flag{flag})
+					for _, v := range values {
+						if err := fs.Set(flag.GetName(), v); err != nil {
+							return err
+						}
+					}
+					path := root.configPath()
+					if _, ok := flag.(SliceFlag); ok {
+						return SetConfigValues(path, flag.GetName(), values)
+					}
+					if len(values) > 1 {
+						return fmt.Errorf("flag %q only takes a single value", flag.GetName())
+					}
+					return SetConfigValue(path, flag.GetName(), values[0])
+				},
+			},
+			{
+				Usage: "get <flag>",
+				Help:  "Print the default value of a flag",
+				Exec: func(c *Context) error {
+					if len(c.Args()) != 1 {
+						return errors.New("expected the name of a flag")
+					}
+					root := c.cmd.root()
+					flag, err := root.lookupTreeFlag(c.Args()[0])
+					if err != nil {
+						return err
+					}
+					r, err := NewConfigFileResolver(root.configPath())
+					if err != nil {
+						return err
+					}
+					v, found := r.Resolve(flag)
+					if !found {
+						return fmt.Errorf("flag %q is not set in %s", flag.GetName(), root.configPath())
+					}
+					_, err = fmt.Fprintln(root.options().Writer, v)
+					return err
+				},
+			},
+			{
+				Usage: "unset <flag>",
+				Help:  "Remove the default value of a flag",
+				Exec: func(c *Context) error {
+					if len(c.Args()) != 1 {
+						return errors.New("expected the name of a flag")
+					}
+					root := c.cmd.root()
+					flag, err := root.lookupTreeFlag(c.Args()[0])
+					if err != nil {
+						return err
+					}
+					return UnsetConfigValue(root.configPath(), flag.GetName())
+				},
+			},
+			{
+				Usage: "path",
+				Help:  "Print the path of the config file",
+				Exec: func(c *Context) error {
+					root := c.cmd.root()
+					_, err := fmt.Fprintln(root.options().Writer, root.configPath())
+					return err
+				},
+			},
+		},
+	}
+}
+
+// configPath returns the path of the config file used by the config command.
+func (c *Command) configPath() string {
+	if path := c.options().ConfigFile; path != "" {
+		return path
+	}
+	return c.options().appConfigPath(c.name())
+}
+
+// lookupTreeFlag returns the flag with the name that is defined by a command in the tree. Subcommands that have not
+// been initialized are included.
+func (c *Command) lookupTreeFlag(name string) (Flag, error) {
+	if f := c.findTreeFlag(name); f != nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("flag %q is not defined", name)
+}
+
+// findTreeFlag implements lookupTreeFlag.
+func (c *Command) findTreeFlag(name string) Flag {
+	for _, f := range c.declaredFlags() {
+		if f.GetName() == name {
+			return f
+		}
+	}
+	for _, subcommand := range c.Subcommands {
+		if f := subcommand.findTreeFlag(name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
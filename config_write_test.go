@@ -0,0 +1,104 @@
+package cli_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestSetConfigValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := `# Defaults for deployer.
+region: us-east-1 # Closest region.
+
+# Tags to apply.
+tag: [a, b]
+`
+	if err := ioutil.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	eq(t, nil, cli.SetConfigValue(path, "region", "eu-west-1"))
+	eq(t, nil, cli.SetConfigValue(path, "replicas", "3"))
+	eq(t, nil, cli.SetConfigValues(path, "tag", []string{"c", "d,e"}))
+	eq(t, nil, cli.UnsetConfigValue(path, "missing"))
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Defaults for deployer.
+region: eu-west-1 # Closest region.
+# Tags to apply.
+tag: [c, 'd,e']
+replicas: 3
+`
+	eq(t, expected, string(b))
+
+	eq(t, nil, cli.UnsetConfigValue(path, "tag"))
+	b, _ = ioutil.ReadFile(path)
+	eq(t, "# Defaults for deployer.\nregion: eu-west-1 # Closest region.\nreplicas: 3\n", string(b))
+	eq(t, os.FileMode(0o644), fileMode(t, path))
+
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "deployer", "config.yaml")
+		eq(t, nil, cli.UnsetConfigValue(path, "region"))
+		eq(t, nil, cli.SetConfigValue(path, "region", "eu-west-1"))
+		b, _ := ioutil.ReadFile(path)
+		eq(t, "region: eu-west-1\n", string(b))
+		eq(t, os.FileMode(0o600), fileMode(t, path))
+	})
+}
+
+// fileMode returns the permissions of the file, or skips the test on Windows (which does not have them).
+func fileMode(t *testing.T, path string) os.FileMode {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not supported on windows")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Mode().Perm()
+}
+
+func TestConfigCommand(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "config.yaml")
+		out  bytes.Buffer
+	)
+	c := newConfigCommand()
+	c.Opts = cli.Options{ConfigCommand: true, ConfigFile: path, Writer: &out}
+
+	eq(t, nil, c.Execute([]string{"config", "set", "region", "us-east-1"}))
+	eq(t, nil, c.Execute([]string{"config", "set", "tag", "a", "b"}))
+	eq(t, nil, c.Execute([]string{"config", "set", "replicas", "5"}))
+	eq(t, nil, c.Execute([]string{"config", "unset", "replicas"}))
+	eq(t, nil, c.Execute([]string{"config", "get", "tag"}))
+	eq(t, nil, c.Execute([]string{"config", "path"}))
+	eq(t, "a,b\n"+path+"\n", out.String())
+
+	b, _ := ioutil.ReadFile(path)
+	eq(t, "region: us-east-1\ntag: [a, b]\n", string(b))
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"config", "set", "replicas", "many"}, expected: `invalid argument "many" for "--replicas" flag: strconv.ParseInt: parsing "many": invalid syntax`},
+		{args: []string{"config", "set", "region", "a", "b"}, expected: `flag "region" only takes a single value`},
+		{args: []string{"config", "set", "unknown", "a"}, expected: `flag "unknown" is not defined`},
+		{args: []string{"config", "get", "replicas"}, expected: `flag "replicas" is not set in ` + path},
+	} {
+		err := c.Execute(tc.args)
+		if err == nil {
+			t.Fatalf("expected an error for %v", tc.args)
+		}
+		eq(t, tc.expected, err.Error())
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Context is passed to the Exec function of the command being executed. It embeds the parsed pflag.FlagSet, which
+// gives access to the positional arguments and the values of all flags available to the command.
+type Context struct {
+	*pflag.FlagSet
+
+	cmd     *Command
+	in      *bufio.Reader
+	logger  *slog.Logger
+	rawArgs []string
+	result  interface{}
+	chain   *chain
+}
+
+// ArgsAfterDash returns the positional arguments that were given after the "--" terminator. These are passed through
+// verbatim, even if they look like flags or subcommand names.
+func (c *Context) ArgsAfterDash() []string {
+	if i := c.ArgsLenAtDash(); i >= 0 {
+		return c.Args()[i:]
+	}
+	return nil
+}
+
+// RawArgs returns the arguments exactly as they were given to Execute (i.e. before response files, aliases, subcommands
+// and flags are processed), so that commands can reconstruct the invocation, e.g. to re-execute themselves under sudo
+// using os.Executable and the RawArgs.
+func (c *Context) RawArgs() []string {
+	return append([]string(nil), c.rawArgs...)
+}
+
+// SetResult sets the result of the command, which is returned by Command.ExecuteResult. This lets programs that embed
+// the application (e.g. an HTTP adapter or tests) consume structured results instead of parsing the printed output.
+// Setting the result again replaces the previous result.
+func (c *Context) SetResult(v interface{}) {
+	c.result = v
+}
+
+// Root returns the root command of the application, e.g. to access its metadata or VersionInfo.
+func (c *Context) Root() *Command {
+	return c.cmd.root()
+}
+
+// AppName returns the name of the application, i.e. the name of the root command.
+func (c *Context) AppName() string {
+	return c.cmd.root().name()
+}
+
+// AppVersion returns the version of the application, i.e. the Version of the root command.
+func (c *Context) AppVersion() string {
+	return c.cmd.root().Version
+}
+
+// FlagNames returns the (sorted) names of the flags available to the command that have been set, either in the
+// arguments or by a FlagResolver. NFlag (of the embedded pflag.FlagSet) returns the number of flags that were given in
+// the arguments.
+func (c *Context) FlagNames() []string {
+	return c.flagNames(c.cmd.CombinedFlags())
+}
+
+// LocalFlagNames returns the (sorted) names of the flags defined by the command itself (i.e. not by its parents) that
+// have been set, see FlagNames.
+func (c *Context) LocalFlagNames() []string {
+	return c.flagNames(c.cmd.LocalFlags())
+}
+
+// flagNames returns the sorted names of the flags that have been set.
+func (c *Context) flagNames(flags []Flag) []string {
+	var names []string
+	for _, f := range flags {
+		pf := c.Lookup(f.GetName())
+		if pf == nil {
+			continue
+		}
+		if _, resolved := c.cmd.sources[f.GetName()]; pf.Changed || resolved {
+			names = append(names, f.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTestContext returns a Context that can be passed directly to an Exec function in a unit test, without having to
+// construct a Command and go through Execute. The given flags are registered with their values as defaults (the type
+// of each value decides the flag type), and args are parsed as the command line.
+func NewTestContext(args []string, flags map[string]interface{}) (*Context, error) {
+	var fs []Flag
+	for name, value := range flags {
+		f, err := newTestFlag(name, value)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	cmd := &Command{Usage: "test", Flags: fs, Exec: func(*Context) error { return nil }}
+	cmd.Opts.complete()
+	cmd.fs = newFS(cmd.Flags)
+	if err := cmd.fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return &Context{FlagSet: cmd.fs, cmd: cmd, rawArgs: args}, nil
+}
+
+// newTestFlag returns the Flag matching the type of the given value.
+func newTestFlag(name string, value interface{}) (Flag, error) {
+	switch v := value.(type) {
+	case bool:
+		return &BoolFlag{Name: name, Value: v}, nil
+	case []bool:
+		return &BoolSliceFlag{Name: name, Value: v}, nil
+	case time.Duration:
+		return &DurationFlag{Name: name, Value: v}, nil
+	case []time.Duration:
+		return &DurationSliceFlag{Name: name, Value: v}, nil
+	case int:
+		return &IntFlag{Name: name, Value: v}, nil
+	case []int:
+		return &IntSliceFlag{Name: name, Value: v}, nil
+	case int64:
+		return &Int64Flag{Name: name, Value: v}, nil
+	case []int64:
+		return &Int64SliceFlag{Name: name, Value: v}, nil
+	case string:
+		return &StringFlag{Name: name, Value: v}, nil
+	case []string:
+		return &StringSliceFlag{Name: name, Value: v}, nil
+	case map[string]int:
+		return &StringToIntFlag{Name: name, Value: v}, nil
+	case map[string]int64:
+		return &StringToInt64Flag{Name: name, Value: v}, nil
+	case Flag:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T for flag %q", value, name)
+	}
+}
+
+// lookupFlag returns the parsed pflag.Flag for the first flag available to the command that matches the predicate.
+func (c *Context) lookupFlag(match func(Flag) bool) *pflag.Flag {
+	return c.cmd.lookupFlag(match)
+}
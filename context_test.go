@@ -0,0 +1,132 @@
+package cli_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestNewTestContext(t *testing.T) {
+	c, err := cli.NewTestContext([]string{"--times", "5", "hello"}, map[string]interface{}{
+		"times":   3,
+		"debug":   true,
+		"timeout": 2 * time.Second,
+		"tags":    []string{"a", "b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	times, err := c.GetInt("times")
+	eq(t, nil, err)
+	eq(t, 5, times)
+
+	debug, err := c.GetBool("debug")
+	eq(t, nil, err)
+	eq(t, true, debug)
+
+	timeout, err := c.GetDuration("timeout")
+	eq(t, nil, err)
+	eq(t, 2*time.Second, timeout)
+
+	tags, err := c.GetStringSlice("tags")
+	eq(t, nil, err)
+	eq(t, []string{"a", "b"}, tags)
+
+	eq(t, []string{"hello"}, c.Args())
+}
+
+func TestNewTestContextUnsupportedType(t *testing.T) {
+	_, err := cli.NewTestContext(nil, map[string]interface{}{"ratio": 0.5})
+	eq(t, errors.New(`unsupported type float64 for flag "ratio"`), err)
+}
+
+func TestFlagNames(t *testing.T) {
+	var called bool
+	c := cli.Command{
+		Usage: "deployer [command]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region"},
+			&cli.BoolFlag{Name: "verbose"},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{staticResolver{"token": "secret"}}},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "token"},
+					&cli.IntFlag{Name: "replicas", Value: 3},
+					&cli.BoolFlag{Name: "dry-run"},
+				},
+				Exec: func(c *cli.Context) error {
+					called = true
+					eq(t, 2, c.NFlag())
+					eq(t, []string{"dry-run", "region", "token"}, c.FlagNames())
+					eq(t, []string{"dry-run", "token"}, c.LocalFlagNames())
+					return nil
+				},
+			},
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--region", "eu-west-1", "deploy", "--dry-run"}))
+	eq(t, true, called)
+}
+
+func TestRawArgs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "args"), []byte("--replicas\n5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var raw []string
+	c := cli.Command{
+		Usage: "deployer [command]",
+		Opts:  cli.Options{ResponseFiles: true, Aliases: map[string]string{"d": "deploy"}},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{&cli.IntFlag{Name: "replicas"}},
+				Exec: func(c *cli.Context) error {
+					raw = c.RawArgs()
+					return nil
+				},
+			},
+		},
+	}
+	args := []string{"d", "@" + filepath.Join(dir, "args"), "--", "extra"}
+	eq(t, nil, c.Execute(args))
+	eq(t, args, raw)
+}
+
+func TestAppMetadata(t *testing.T) {
+	var (
+		called bool
+		root   *cli.Command
+	)
+	root = &cli.Command{
+		Usage:   "deployer [command]",
+		Version: "1.2.3",
+		Subcommands: []*cli.Command{
+			{
+				Usage: "services [command]",
+				Subcommands: []*cli.Command{
+					{
+						Usage: "deploy [flags]",
+						Exec: func(ctx *cli.Context) error {
+							called = true
+							eq(t, "deployer", ctx.AppName())
+							eq(t, "1.2.3", ctx.AppVersion())
+							eq(t, true, ctx.Root() == root)
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+	eq(t, nil, root.Execute([]string{"services", "deploy"}))
+	eq(t, true, called)
+}
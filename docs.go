@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenMarkdownTree writes one Markdown file per command in the tree to dir. The files are named after the command
+// path, e.g. "printer_repeat.md", and link to their parent and child commands.
+func GenMarkdownTree(c *Command, dir string) error {
+	if err := c.initializeTree(); err != nil {
+		return err
+	}
+	return genMarkdownTree(c, dir)
+}
+
+// genMarkdownTree recurses through an initialized command tree.
+func genMarkdownTree(c *Command, dir string) error {
+	f, err := os.Create(filepath.Join(dir, markdownFilename(c)))
+	if err != nil {
+		return err
+	}
+	if err := genMarkdown(c, f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	for _, subcommand := range c.visibleSubcommands() {
+		if err := genMarkdownTree(subcommand, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenMarkdown writes the Markdown documentation for a single command to w.
+func GenMarkdown(c *Command, w io.Writer) error {
+	if err := c.initialize(); err != nil {
+		return err
+	}
+	return genMarkdown(c, w)
+}
+
+// genMarkdown writes the documentation for an initialized command.
+func genMarkdown(c *Command, w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", c.path())
+	if c.Help != "" {
+		fmt.Fprintf(&b, "%s\n\n", c.Help)
+	}
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n", c.usage())
+
+	if flags := c.visibleFlags(c.LocalFlags()); len(flags) > 0 {
+		fmt.Fprint(&b, "\n## Flags\n\n")
+		writeMarkdownFlags(&b, c, flags)
+	}
+	if flags := c.visibleFlags(c.GlobalFlags()); len(flags) > 0 {
+		fmt.Fprint(&b, "\n## Global Flags\n\n")
+		writeMarkdownFlags(&b, c, flags)
+	}
+	if c.Examples != "" {
+		fmt.Fprintf(&b, "\n## Examples\n\n```\n%s\n```\n", strings.TrimSpace(c.Examples))
+	}
+	if subcommands := c.visibleSubcommands(); len(subcommands) > 0 {
+		fmt.Fprint(&b, "\n## Commands\n\n")
+		for _, subcommand := range subcommands {
+			fmt.Fprintf(&b, "* [%s](%s) - %s\n", subcommand.path(), markdownFilename(subcommand), subcommand.Help)
+		}
+	}
+	if c.parent != nil {
+		fmt.Fprintf(&b, "\n## See Also\n\n* [%s](%s) - %s\n", c.parent.path(), markdownFilename(c.parent), c.parent.Help)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownFlags writes the flags of the command as a Markdown table.
+func writeMarkdownFlags(w io.Writer, c *Command, flags []Flag) {
+	fs := c.flagSet(flags)
+
+	fmt.Fprintln(w, "| Flag | Type | Default | Environment | Description |")
+	fmt.Fprintln(w, "| ---- | ---- | ------- | ----------- | ----------- |")
+	for _, f := range flags {
+		pf := fs.Lookup(f.GetName())
+
+		name := "`--" + f.GetName() + "`"
+		if s := f.GetShorthand(); s != "" {
+			name = "`-" + s + "`, " + name
+		}
+		var def, env string
+		if d, show := c.usageDefault(f, pf); show && d != "" && d != "[]" && d != "false" {
+			def = "`" + d + "`"
+		}
+		if vars := f.GetEnvVar(); len(vars) > 0 {
+			env = "`" + strings.Join(vars, "`, `") + "`"
+		}
+		usage := f.GetUsage()
+		if c.isRequired(f) {
+			usage = "**Required.** " + usage
+		} else if cf, ok := f.(ConditionalFlag); ok && cf.GetRequiredIf() != "" {
+			usage = "**Required if `" + cf.GetRequiredIf() + "`.** " + usage
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", name, pf.Value.Type(), def, env, markdownEscape(usage))
+	}
+}
+
+// markdownEscape escapes characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// markdownFilename returns the name of the Markdown file for the command.
+func markdownFilename(c *Command) string {
+	return strings.ReplaceAll(c.path(), " ", "_") + ".md"
+}
@@ -0,0 +1,89 @@
+package cli_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func newDocsCommand() *cli.Command {
+	return &cli.Command{
+		Usage: "printer [flags] [command]",
+		Help:  "Print things",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "debug, d",
+				Usage: "Enable debug logging",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage:    "repeat [flags] <arg>",
+				Help:     "Repeatedly print the given argument",
+				Examples: "printer repeat --times 5 hello",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:   "times, t",
+						Usage:  "Number of times to print the argument",
+						Value:  3,
+						EnvVar: []string{"PRINTER_REPEAT_TIMES"},
+					},
+					&cli.StringFlag{
+						Name:     "delimiter",
+						Usage:    "Delimiter to use when printing",
+						Required: true,
+					},
+				},
+				Exec: func(c *cli.Context) error { return nil },
+			},
+		},
+	}
+}
+
+func TestGenMarkdownTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := cli.GenMarkdownTree(newDocsCommand(), dir); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	eq(t, nil, err)
+	eq(t, []string{filepath.Join(dir, "printer.md"), filepath.Join(dir, "printer_repeat.md")}, files)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "printer_repeat.md"))
+	eq(t, nil, err)
+	eq(t, "# printer repeat\n"+
+		"\n"+
+		"Repeatedly print the given argument\n"+
+		"\n"+
+		"## Usage\n"+
+		"\n"+
+		"```\n"+
+		"printer repeat [flags] <arg>\n"+
+		"```\n"+
+		"\n"+
+		"## Flags\n"+
+		"\n"+
+		"| Flag | Type | Default | Environment | Description |\n"+
+		"| ---- | ---- | ------- | ----------- | ----------- |\n"+
+		"| `-t`, `--times` | int | `3` | `PRINTER_REPEAT_TIMES` | Number of times to print the argument |\n"+
+		"| `--delimiter` | string |  |  | **Required.** Delimiter to use when printing |\n"+
+		"\n"+
+		"## Global Flags\n"+
+		"\n"+
+		"| Flag | Type | Default | Environment | Description |\n"+
+		"| ---- | ---- | ------- | ----------- | ----------- |\n"+
+		"| `-d`, `--debug` | bool |  |  | Enable debug logging |\n"+
+		"\n"+
+		"## Examples\n"+
+		"\n"+
+		"```\n"+
+		"printer repeat --times 5 hello\n"+
+		"```\n"+
+		"\n"+
+		"## See Also\n"+
+		"\n"+
+		"* [printer](printer.md) - Print things\n", string(b))
+}
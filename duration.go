@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// durationUnits matches the days ("d") and weeks ("w") in a duration (e.g. "1d12h"), which are not supported by
+// time.ParseDuration.
+var durationUnits = regexp.MustCompile(`(\d+(?:\.\d*)?|\.\d+)([dw])`)
+
+// expandDurationUnits replaces days and weeks in s with the equivalent number of hours, e.g. "1w2d" becomes
+// "168h48h". Since none of the units supported by time.ParseDuration contain a "d" or "w", this also works for a list
+// of durations.
+func expandDurationUnits(s string) string {
+	return durationUnits.ReplaceAllStringFunc(s, func(m string) string {
+		n, _ := strconv.ParseFloat(m[:len(m)-1], 64)
+		hours := 24.0
+		if m[len(m)-1] == 'w' {
+			hours *= 7
+		}
+		return strconv.FormatFloat(n*hours, 'f', -1, 64) + "h"
+	})
+}
+
+// applyDurationUnits wraps the value of a duration (or duration slice) flag, so that days and weeks can be used.
+func applyDurationUnits(fs *pflag.FlagSet, name string) {
+	f := fs.Lookup(name)
+	if sv, ok := f.Value.(sliceValue); ok {
+		f.Value = &durationSliceValue{durationValue: durationValue{Value: f.Value}, slice: sv}
+		return
+	}
+	f.Value = &durationValue{Value: f.Value}
+}
+
+// durationValue implements pflag.Value for durations with days and weeks.
+type durationValue struct {
+	pflag.Value
+}
+
+// Set implements pflag.Value.
+func (v *durationValue) Set(s string) error {
+	return v.Value.Set(expandDurationUnits(s))
+}
+
+// durationSliceValue implements pflag.Value and sliceValue for duration slices with days and weeks.
+type durationSliceValue struct {
+	durationValue
+	slice sliceValue
+}
+
+// Append implements sliceValue.
+func (v *durationSliceValue) Append(s string) error {
+	return v.slice.Append(expandDurationUnits(s))
+}
+
+// Replace implements sliceValue.
+func (v *durationSliceValue) Replace(values []string) error {
+	expanded := make([]string, len(values))
+	for i, s := range values {
+		expanded[i] = expandDurationUnits(s)
+	}
+	return v.slice.Replace(expanded)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+// envCommand returns the env subcommand, which prints the effective value and source of each flag for the command
+// selected by its arguments.
+func envCommand() *Command {
+	return &Command{
+		Usage:           "env [command] [flags]",
+		Help:            "Print the effective flag values for a command",
+		Hidden:          true,
+		SkipFlagParsing: true,
+		Exec: func(c *Context) error {
+			root := c.cmd.root()
+			target, args, offset, err := root.dispatch(c.Args(), 0)
+			if err != nil {
+				return err
+			}
+			err = target.parseFlags(args, offset)
+			if errors.Is(err, pflag.ErrHelp) || errors.Is(err, errVersion) || target.sources == nil {
+				return err
+			}
+			if len(target.subcommands()) > 0 {
+				err = nil // Intermediate commands (e.g. the root) are valid targets, even though they cannot be executed.
+			}
+
+			t := NewTable(root.options().Writer, "FLAG", "VALUE", "SOURCE")
+			for _, f := range target.visibleFlags(target.CombinedFlags()) {
+				pf := target.fs.Lookup(f.GetName())
+				value, source := pf.Value.String(), target.sources[f.GetName()]
+				if source == "" {
+					source = "default"
+				}
+				if s, ok := f.(SecretFlag); ok && s.IsSecret() && value != "" && value != "[]" {
+					value = "********"
+				}
+				t.AddRow(f.GetName(), value, source)
+			}
+			if ferr := t.Flush(); ferr != nil {
+				return ferr
+			}
+			return err
+		},
+	}
+}
+
+// FlagSource returns where the value of the flag came from, i.e. "arg" if it was given in the arguments, the source
+// reported by the FlagResolver (see FlagSource), or "default" if the flag was not set.
+func (c *Context) FlagSource(name string) string {
+	if s, ok := c.cmd.sources[name]; ok {
+		return s
+	}
+	return "default"
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownEnvPolicy decides what happens when there are environment variables with the Options.EnvPrefix that are not
+// used by any flag (or option) of the application, e.g. a misspelled MYCLI_REGOIN.
+type UnknownEnvPolicy int
+
+const (
+	// UnknownEnvIgnore ignores unknown environment variables.
+	UnknownEnvIgnore UnknownEnvPolicy = iota
+
+	// UnknownEnvWarn prints a warning to the ErrWriter for unknown environment variables.
+	UnknownEnvWarn
+
+	// UnknownEnvError returns an ErrUnknownEnvVars for unknown environment variables.
+	UnknownEnvError
+)
+
+// ErrUnknownEnvVars is returned when there are unknown environment variables and the UnknownEnvPolicy is
+// UnknownEnvError.
+type ErrUnknownEnvVars struct {
+	// Names of the unknown environment variables (sorted).
+	Names []string
+}
+
+// Error implements errors.Error.
+func (e *ErrUnknownEnvVars) Error() string {
+	return fmt.Sprintf("unknown environment variables %v", e.Names)
+}
+
+// checkEnvVars applies the UnknownEnvPolicy to the environment variables with the Options.EnvPrefix.
+func (c *Command) checkEnvVars() error {
+	opts := c.options()
+	if opts.EnvPrefix == "" || opts.UnknownEnvVars == UnknownEnvIgnore {
+		return nil
+	}
+	known := c.root().knownEnvVars(make(map[string]bool))
+	for _, k := range []string{opts.FeatureGatesEnvVar, opts.TraceEnvVar, "NO_COLOR", "CLICOLOR_FORCE"} {
+		known[k] = true
+	}
+	if opts.UpdateChecker != nil {
+		known[opts.UpdateChecker.disableEnvVar(c.root().name())] = true
+	}
+
+	var unknown []string
+	for _, kv := range opts.environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(k, opts.EnvPrefix) && !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	if opts.UnknownEnvVars == UnknownEnvWarn {
+		fmt.Fprintf(opts.ErrWriter, c.tr("warning: unknown environment variables %v")+"\n", unknown)
+		return nil
+	}
+	return &ErrUnknownEnvVars{Names: unknown}
+}
+
+// knownEnvVars adds the environment variables of the flags in the command tree to known. Subcommands that have not
+// been initialized are included, since the variables might be meant for a different command.
+func (c *Command) knownEnvVars(known map[string]bool) map[string]bool {
+	for _, f := range c.declaredFlags() {
+		for _, k := range f.GetEnvVar() {
+			known[envVarName(k)] = true
+		}
+	}
+	for _, subcommand := range c.Subcommands {
+		subcommand.knownEnvVars(known)
+	}
+	return known
+}
+
+// declaredFlags returns the local flags of the command, including the flags of its FlagGroups, without requiring the
+// command to be initialized.
+func (c *Command) declaredFlags() []Flag {
+	flags := c.Flags
+	if c.parent == nil {
+		flags = c.ownFlags()
+	}
+	for _, group := range c.FlagGroups {
+		flags = append(flags[:len(flags):len(flags)], group.Flags...)
+	}
+	return flags
+}
+
+// warnEnvVarConflicts prints a warning for each flag that was resolved from an environment variable when other
+// environment variables of the flag are set to different values (see Options.WarnEnvVarConflicts).
+func (c *Command) warnEnvVarConflicts(flags []Flag, sources map[string]string) {
+	if !c.options().WarnEnvVarConflicts {
+		return
+	}
+	for _, f := range flags {
+		used, conflicts := envVarConflicts(f, c.options().lookupEnv)
+		if len(conflicts) == 0 || sources[f.GetName()] != c.options().decorateEnvVar(used) {
+			continue
+		}
+		for i, k := range conflicts {
+			conflicts[i] = c.options().decorateEnvVar(k)
+		}
+		msg := c.tr("warning: flag %q is set from %s, ignoring the different value of %s")
+		fmt.Fprintf(c.options().ErrWriter, msg+"\n", f.GetName(), c.options().decorateEnvVar(used), strings.Join(conflicts, ", "))
+	}
+}
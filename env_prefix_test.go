@@ -0,0 +1,155 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestUnknownEnvVars(t *testing.T) {
+	for k, v := range map[string]string{"CLI_TEST_REGOIN": "eu-west-1", "CLI_TEST_REPLICAS": "3", "CLI_TEST_FEATURES": "beta"} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	tests := []struct {
+		description    string
+		policy         cli.UnknownEnvPolicy
+		expectedErr    error
+		expectedOutput string
+	}{
+		{
+			description: "ignore",
+			policy:      cli.UnknownEnvIgnore,
+		},
+		{
+			description:    "warn",
+			policy:         cli.UnknownEnvWarn,
+			expectedOutput: "warning: unknown environment variables [CLI_TEST_REGOIN]\n",
+		},
+		{
+			description: "error",
+			policy:      cli.UnknownEnvError,
+			expectedErr: &cli.ErrUnknownEnvVars{Names: []string{"CLI_TEST_REGOIN"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "deployer [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region", EnvVar: []string{"CLI_TEST_REGION"}}},
+				Subcommands: []*cli.Command{
+					{Usage: "deploy", Exec: func(c *cli.Context) error { return nil }},
+					{
+						Usage: "scale",
+						Flags: []cli.Flag{&cli.IntFlag{Name: "replicas", EnvVar: []string{"$CLI_TEST_REPLICAS"}}},
+						Exec:  func(c *cli.Context) error { return nil },
+					},
+				},
+				Opts: cli.Options{
+					ErrWriter:          &b,
+					EnvPrefix:          "CLI_TEST_",
+					UnknownEnvVars:     tc.policy,
+					FeatureGatesEnvVar: "CLI_TEST_FEATURES",
+				},
+			}
+			err := c.Execute([]string{"deploy"})
+			if tc.expectedErr == nil {
+				eq(t, nil, err)
+			} else {
+				var unknown *cli.ErrUnknownEnvVars
+				eq(t, true, errors.As(err, &unknown))
+				eq(t, tc.expectedErr, unknown)
+			}
+			eq(t, tc.expectedOutput, b.String())
+		})
+	}
+}
+
+func TestUnknownEnvVars_Framework(t *testing.T) {
+	tests := []struct {
+		description string
+		environ     []string
+	}{
+		{
+			description: "default update check opt-out",
+			environ:     []string{"MYCLI_NO_UPDATE_CHECK=1"},
+		},
+		{
+			description: "trace",
+			environ:     []string{"MYCLI_TRACE=1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage:   "mycli",
+				Version: "1.0.0",
+				Exec:    func(c *cli.Context) error { return nil },
+				Opts: cli.Options{
+					Environ:        tc.environ,
+					ErrWriter:      &b,
+					TraceEnvVar:    "MYCLI_TRACE",
+					EnvPrefix:      "MYCLI_",
+					UnknownEnvVars: cli.UnknownEnvError,
+					UpdateChecker: &cli.UpdateChecker{
+						Latest: func(ctx context.Context) (string, error) { return "1.0.0", nil },
+					},
+				},
+			}
+			eq(t, nil, c.Execute(nil))
+		})
+	}
+}
+
+func TestWarnEnvVarConflicts(t *testing.T) {
+	tests := []struct {
+		description    string
+		args           []string
+		env            map[string]string
+		expectedOutput string
+	}{
+		{
+			description: "same values",
+			env:         map[string]string{"CLI_TEST_REGION": "eu-west-1", "CLI_TEST_DEFAULT_REGION": "eu-west-1"},
+		},
+		{
+			description:    "different values",
+			env:            map[string]string{"CLI_TEST_REGION": "eu-west-1", "CLI_TEST_DEFAULT_REGION": "us-east-1"},
+			expectedOutput: "warning: flag \"region\" is set from $CLI_TEST_REGION, ignoring the different value of $CLI_TEST_DEFAULT_REGION\n",
+		},
+		{
+			description: "set in arguments",
+			args:        []string{"--region", "eu-north-1"},
+			env:         map[string]string{"CLI_TEST_REGION": "eu-west-1", "CLI_TEST_DEFAULT_REGION": "us-east-1"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "region", EnvVar: []string{"CLI_TEST_REGION", "CLI_TEST_DEFAULT_REGION"}},
+				},
+				Opts: cli.Options{ErrWriter: &b, WarnEnvVarConflicts: true},
+				Exec: func(c *cli.Context) error { return nil },
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, tc.expectedOutput, b.String())
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestEnvCommand(t *testing.T) {
+	os.Setenv("CLI_TEST_REGION", "eu-west-1")
+	defer os.Unsetenv("CLI_TEST_REGION")
+
+	var (
+		b      bytes.Buffer
+		help   bytes.Buffer
+		source string
+	)
+	c := cli.Command{
+		Usage: "deployer [command]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region", EnvVar: []string{"CLI_TEST_REGION"}},
+			&cli.StringFlag{Name: "token", Secret: true},
+		},
+		Opts: cli.Options{Writer: &b, ErrWriter: &help, EnvCommand: true},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{&cli.BoolFlag{Name: "dry-run"}, &cli.IntFlag{Name: "replicas", Value: 3}},
+				Exec: func(c *cli.Context) error {
+					source = c.FlagSource("region")
+					return nil
+				},
+			},
+		},
+	}
+
+	eq(t, nil, c.Execute([]string{"env", "deploy", "--dry-run", "--token", "secret"}))
+	expected := `FLAG       VALUE       SOURCE
+dry-run    true        arg
+replicas   3           default
+region     eu-west-1   $CLI_TEST_REGION
+token      ********    arg
+`
+	eq(t, expected, b.String())
+
+	b.Reset()
+	eq(t, nil, c.Execute([]string{"env"}))
+	expected = `FLAG     VALUE       SOURCE
+region   eu-west-1   $CLI_TEST_REGION
+token                default
+`
+	eq(t, expected, b.String())
+
+	eq(t, nil, c.Execute([]string{"deploy"}))
+	eq(t, "$CLI_TEST_REGION", source)
+
+	eq(t, nil, c.Execute([]string{"--help"}))
+	eq(t, true, bytes.Contains(help.Bytes(), []byte("deploy")))
+	eq(t, false, bytes.Contains(help.Bytes(), []byte("env")))
+}
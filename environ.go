@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// environ returns the environment of the application (see Options.Environ).
+func (opts *Options) environ() []string {
+	if opts.Environ != nil {
+		return opts.Environ
+	}
+	return os.Environ()
+}
+
+// lookupEnv returns the value of the environment variable in the environment of the application (see
+// Options.Environ). When a variable is given more than once, the last value is used (same as os/exec).
+func (opts *Options) lookupEnv(key string) (string, bool) {
+	if opts.Environ == nil {
+		return os.LookupEnv(key)
+	}
+	for i := len(opts.Environ) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(opts.Environ[i], "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// getenv returns the value of the environment variable (see lookupEnv), or an empty string if it is not set.
+func (opts *Options) getenv(key string) string {
+	v, _ := opts.lookupEnv(key)
+	return v
+}
+
+// userHomeDir returns the home directory of the user in the environment of the application (see os.UserHomeDir).
+func (opts *Options) userHomeDir() (string, error) {
+	if opts.Environ == nil {
+		return os.UserHomeDir()
+	}
+	key := "HOME"
+	switch runtime.GOOS {
+	case "windows":
+		key = "USERPROFILE"
+	case "plan9":
+		key = "home"
+	}
+	if v := opts.getenv(key); v != "" {
+		return v, nil
+	}
+	return "", fmt.Errorf("$%s is not defined", key)
+}
+
+// userCacheDir returns the cache directory of the user in the environment of the application (see os.UserCacheDir).
+func (opts *Options) userCacheDir() (string, error) {
+	if opts.Environ == nil {
+		return os.UserCacheDir()
+	}
+	switch runtime.GOOS {
+	case "windows":
+		if dir := opts.getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%LocalAppData% is not defined")
+	case "darwin", "ios":
+		home, err := opts.userHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Caches"), nil
+	case "plan9":
+		home, err := opts.userHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "lib", "cache"), nil
+	default:
+		if dir := opts.getenv("XDG_CACHE_HOME"); dir != "" {
+			return dir, nil
+		}
+		home, err := opts.userHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".cache"), nil
+	}
+}
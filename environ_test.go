@@ -0,0 +1,145 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestEnviron(t *testing.T) {
+	tests := []struct {
+		description     string
+		environ         []string
+		expectedRegion  string
+		expectedHost    string
+		expectedUnknown []string
+	}{
+		{
+			description:    "resolves flags",
+			environ:        []string{"APP_REGION=eu-west-1", "APP_HOST=api.${region}.example.com"},
+			expectedRegion: "eu-west-1",
+			expectedHost:   "api.eu-west-1.example.com",
+		},
+		{
+			description:    "last value is used",
+			environ:        []string{"APP_REGION=eu-west-1", "APP_REGION=us-east-1"},
+			expectedRegion: "us-east-1",
+		},
+		{
+			description: "empty environment",
+			environ:     []string{},
+		},
+		{
+			description:     "unknown variables",
+			environ:         []string{"APP_REGOIN=eu-west-1"},
+			expectedUnknown: []string{"APP_REGOIN"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			var region, host string
+			c := cli.Command{
+				Usage: "app",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "region", EnvVar: []string{"APP_REGION"}},
+					&cli.StringFlag{Name: "host", EnvVar: []string{"APP_HOST"}},
+				},
+				Opts: cli.Options{
+					Environ:              tc.environ,
+					EnvPrefix:            "APP_",
+					UnknownEnvVars:       cli.UnknownEnvError,
+					ExpandResolvedValues: true,
+					ErrWriter:            io.Discard,
+				},
+				Exec: func(c *cli.Context) error {
+					region, _ = c.GetString("region")
+					host, _ = c.GetString("host")
+					return nil
+				},
+			}
+			err := c.Execute(nil)
+			if tc.expectedUnknown != nil {
+				var e *cli.ErrUnknownEnvVars
+				eq(t, true, errors.As(err, &e))
+				eq(t, tc.expectedUnknown, e.Names)
+				return
+			}
+			eq(t, nil, err)
+			eq(t, tc.expectedRegion, region)
+			eq(t, tc.expectedHost, host)
+		})
+	}
+}
+
+func TestEnviron_Paths(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the XDG directories are only used on linux and other unix systems")
+	}
+	dir := t.TempDir()
+	environ := []string{
+		"HOME=" + filepath.Join(dir, "home"),
+		"XDG_CONFIG_HOME=" + filepath.Join(dir, "config"),
+		"XDG_CACHE_HOME=" + filepath.Join(dir, "cache"),
+	}
+
+	t.Run("config file", func(t *testing.T) {
+		var out bytes.Buffer
+		c := newConfigCommand()
+		c.Opts = cli.Options{ConfigCommand: true, Environ: environ, Writer: &out}
+		eq(t, nil, c.Execute([]string{"config", "path"}))
+		eq(t, filepath.Join(dir, "config", "deployer", "config.yaml")+"\n", out.String())
+	})
+
+	t.Run("update cache", func(t *testing.T) {
+		c := cli.Command{
+			Usage:   "printer",
+			Version: "v1.2.3",
+			Exec:    func(c *cli.Context) error { return nil },
+			Opts: cli.Options{
+				Environ:   environ,
+				ErrWriter: io.Discard,
+				UpdateChecker: &cli.UpdateChecker{
+					Latest: func(context.Context) (string, error) { return "v1.2.3", nil },
+				},
+			},
+		}
+		eq(t, nil, c.Execute(nil))
+		_, err := os.Stat(filepath.Join(dir, "cache", "printer", "update-check.json"))
+		eq(t, nil, err)
+	})
+
+	t.Run("completion script", func(t *testing.T) {
+		var out bytes.Buffer
+		c := newCompletionCommand(cli.Options{Environ: []string{"HOME=" + filepath.Join(dir, "home")}, Writer: &out})
+		eq(t, nil, c.Execute([]string{"completion", "install", "--shell", "zsh", "--dry-run"}))
+		eq(t, "Would install the zsh completion script to "+filepath.Join(dir, "home", ".zsh", "completions", "_app")+"\n", out.String())
+	})
+}
+
+func TestEnviron_RunProcess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	var out bytes.Buffer
+	c := cli.Command{
+		Usage: "wrapper",
+		Opts:  cli.Options{Environ: []string{"APP_REGION=eu-west-1"}, Writer: &out},
+		Exec: func(c *cli.Context) error {
+			return c.RunProcess("/bin/sh", "-c", `echo "$APP_REGION $HOME"`)
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, "eu-west-1 \n", out.String())
+}
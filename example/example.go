@@ -40,12 +40,12 @@ func main() {
 	}
 	if err := c.Execute(os.Args[1:]); err != nil {
 		fmt.Fprintln(c.Opts.ErrWriter, err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
 func echo(c *cli.Context) error {
-	fmt.Println(strings.Join(c.Args(), " "))
+	c.Println(strings.Join(c.Args(), " "))
 	return nil
 }
 
@@ -55,7 +55,7 @@ func repeat(c *cli.Context) error {
 		return err
 	}
 	for i := 0; i < times; i++ {
-		fmt.Println(c.Arg(0))
+		c.Println(c.Arg(0))
 	}
 	return nil
 }
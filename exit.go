@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ExitCoder is implemented by errors that decide the exit code of the application (see ExitCode).
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// ExitError is an error with an exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements errors.Error.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode implements ExitCoder.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// ExitCode returns the exit code for an error returned by Command.Execute, which is 0 for nil errors, the code of
+// the first ExitCoder in the chain of errors, or 1 for other errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
+}
+
+// RunProcess runs an external command using the Reader, Writer and ErrWriter of the Context as its standard input,
+// output and error, and the environment of the application (see Options.Environ). If the command exits with a non-zero status, an ExitError with the same exit code is returned so
+// that a wrapper CLI can forward it.
+func (c *Context) RunProcess(name string, args ...string) error {
+	opts := c.cmd.options()
+	cmd := exec.Command(name, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = opts.Reader, opts.Writer, opts.ErrWriter
+	if opts.Environ != nil {
+		cmd.Env = opts.Environ
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return &ExitError{Code: exitErr.ExitCode(), Err: err}
+	}
+	return err
+}
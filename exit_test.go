@@ -0,0 +1,36 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestExitCode(t *testing.T) {
+	eq(t, 0, cli.ExitCode(nil))
+	eq(t, 1, cli.ExitCode(errors.New("failed")))
+	eq(t, 4, cli.ExitCode(fmt.Errorf("wrapped: %w", &cli.ExitError{Code: 4})))
+}
+
+func TestRunProcess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	var stdout, stderr bytes.Buffer
+	c := cli.Command{
+		Usage: "wrapper",
+		Opts:  cli.Options{Reader: bytes.NewBufferString("input"), Writer: &stdout, ErrWriter: &stderr},
+		Exec: func(c *cli.Context) error {
+			return c.RunProcess("sh", "-c", "cat; echo err >&2; exit 3")
+		},
+	}
+	err := c.Execute([]string{})
+	eq(t, 3, cli.ExitCode(err))
+	eq(t, "exit status 3", err.Error())
+	eq(t, "input", stdout.String())
+	eq(t, "err\n", stderr.String())
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrFeatureNotEnabled is returned when executing a command, or setting a flag, that is behind a feature gate which
+// is not enabled.
+type ErrFeatureNotEnabled struct {
+	Feature string
+
+	tr func(string) string
+}
+
+// Error implements errors.Error.
+func (e *ErrFeatureNotEnabled) Error() string {
+	return fmt.Sprintf(translate(e.tr, "feature %q is not enabled"), e.Feature)
+}
+
+// featureEnabled returns true if the feature gate is empty, or if it is enabled by Options.FeatureGates or the
+// Options.FeatureGatesEnvVar.
+func (c *Command) featureEnabled(gate string) bool {
+	if gate == "" {
+		return true
+	}
+	opts := c.options()
+	if enabled, ok := opts.FeatureGates[gate]; ok {
+		return enabled
+	}
+	if opts.FeatureGatesEnvVar != "" {
+		for _, g := range strings.Split(opts.getenv(opts.FeatureGatesEnvVar), ",") {
+			if strings.TrimSpace(g) == gate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commandEnabled returns an ErrFeatureNotEnabled if the feature gate of the command (or one of its parents) is not
+// enabled.
+func (c *Command) commandEnabled() error {
+	for p := c; p != nil; p = p.parent {
+		if !c.featureEnabled(p.FeatureGate) {
+			return &ErrFeatureNotEnabled{Feature: p.FeatureGate, tr: c.tr}
+		}
+	}
+	return nil
+}
+
+// flagEnabled returns true if the flag is not behind a feature gate that is disabled.
+func (c *Command) flagEnabled(f Flag) bool {
+	gf, ok := f.(GatedFlag)
+	return !ok || c.featureEnabled(gf.GetFeatureGate())
+}
+
+// enabledFlags returns the flags that are enabled.
+func (c *Command) enabledFlags(flags []Flag) []Flag {
+	var enabled []Flag
+	for _, f := range flags {
+		if c.flagEnabled(f) {
+			enabled = append(enabled, f)
+		}
+	}
+	return enabled
+}
+
+// checkGatedFlags returns an ErrFeatureNotEnabled if a flag that is behind a disabled feature gate has been set.
+func (c *Command) checkGatedFlags() error {
+	for _, f := range c.CombinedFlags() {
+		if c.flagEnabled(f) {
+			continue
+		}
+		if pf := c.fs.Lookup(f.GetName()); pf != nil && pf.Changed {
+			return &ErrFeatureNotEnabled{Feature: f.(GatedFlag).GetFeatureGate(), tr: c.tr}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestFeatureGates(t *testing.T) {
+	newCommand := func(gates map[string]bool, b *bytes.Buffer) *cli.Command {
+		exec := func(c *cli.Context) error { return nil }
+		return &cli.Command{
+			Usage: "mycli [command]",
+			Opts:  cli.Options{ErrWriter: b, FeatureGates: gates, FeatureGatesEnvVar: "CLI_TEST_FEATURES"},
+			Subcommands: []*cli.Command{
+				{
+					Usage: "deploy [flags]",
+					Flags: []cli.Flag{&cli.BoolFlag{Name: "canary", Usage: "Canary deploy", FeatureGate: "canary"}},
+					Exec:  exec,
+				},
+				{Usage: "preview", Help: "Preview command", FeatureGate: "preview", Exec: exec},
+			},
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var b bytes.Buffer
+		c := newCommand(nil, &b)
+
+		var notEnabled *cli.ErrFeatureNotEnabled
+		err := c.Execute([]string{"preview"})
+		eq(t, true, errors.As(err, &notEnabled))
+		eq(t, "parsing command: feature \"preview\" is not enabled", err.Error())
+
+		err = c.Execute([]string{"deploy", "--canary"})
+		eq(t, true, errors.As(err, &notEnabled))
+		eq(t, "canary", notEnabled.Feature)
+
+		eq(t, nil, c.Execute([]string{"--help"}))
+		eq(t, false, strings.Contains(b.String(), "preview"))
+		eq(t, nil, c.Execute([]string{"deploy", "--help"}))
+		eq(t, false, strings.Contains(b.String(), "canary"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		var b bytes.Buffer
+		c := newCommand(map[string]bool{"preview": true, "canary": true}, &b)
+
+		eq(t, nil, c.Execute([]string{"preview"}))
+		eq(t, nil, c.Execute([]string{"deploy", "--canary"}))
+		eq(t, nil, c.Execute([]string{"--help"}))
+		eq(t, true, strings.Contains(b.String(), "Preview command"))
+	})
+
+	t.Run("enabled by env", func(t *testing.T) {
+		os.Setenv("CLI_TEST_FEATURES", "beta, preview")
+		defer os.Unsetenv("CLI_TEST_FEATURES")
+
+		c := newCommand(map[string]bool{"canary": false}, &bytes.Buffer{})
+		eq(t, nil, c.Execute([]string{"preview"}))
+		eq(t, false, c.Execute([]string{"deploy", "--canary"}) == nil)
+	})
+}
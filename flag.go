@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -32,95 +34,538 @@ type Flag interface {
 	IsRequired() bool
 }
 
+// SliceFlag is the interface implemented by flags that accept multiple values.
+type SliceFlag interface {
+	Flag
+
+	// GetDelimiter returns the delimiter used to split resolved values. If empty, values are split the same way they
+	// are on the command line (i.e. by commas).
+	GetDelimiter() string
+
+	// IsAppendResolved returns true if resolved values should be appended to values given on the command line,
+	// instead of only being used when the flag is not given on the command line.
+	IsAppendResolved() bool
+}
+
+// ResolverFlag is the interface implemented by flags that can opt out of being resolved by some of the resolvers,
+// e.g. for flags that should only be given on the command line.
+type ResolverFlag interface {
+	Flag
+
+	// GetDisableResolvers returns the names of the resolvers (see NamedResolver) that should not resolve the flag.
+	GetDisableResolvers() []string
+}
+
+// NamedResolver is the interface implemented by resolvers that have a name, which is used to refer to the resolver
+// (e.g. in ResolverFlag). Resolvers that do not implement NamedResolver are named after their type, e.g.
+// "*mypkg.VaultResolver".
+type NamedResolver interface {
+	FlagResolver
+
+	// Name returns the name of the resolver.
+	Name() string
+}
+
+// PathResolver is the interface implemented by resolvers that resolve flags depending on the command that is being
+// executed, e.g. to look up "app/deploy/region" for the --region flag of "app deploy".
+type PathResolver interface {
+	FlagResolver
+
+	// ResolvePath works like Resolve for the command with the given path, e.g. ["app", "deploy"].
+	ResolvePath(path []string, flag Flag) (string, bool)
+}
+
+// pathResolver binds a PathResolver to the path of a command.
+type pathResolver struct {
+	resolver PathResolver
+	path     []string
+}
+
+// Resolve implements FlagResolver.
+func (r *pathResolver) Resolve(flag Flag) (string, bool) {
+	return r.resolver.ResolvePath(r.path, flag)
+}
+
+// Name implements NamedResolver.
+func (r *pathResolver) Name() string {
+	return resolverName(r.resolver)
+}
+
+// Source implements FlagSource.
+func (r *pathResolver) Source(flag Flag) string {
+	return resolverSource(r.resolver, flag)
+}
+
+// resolverName returns the name of the resolver (see NamedResolver).
+func resolverName(r FlagResolver) string {
+	if n, ok := r.(NamedResolver); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", r)
+}
+
+// resolverDisabled returns true if the flag has opted out of being resolved by the resolver (see ResolverFlag).
+func resolverDisabled(flag Flag, r FlagResolver) bool {
+	rf, ok := flag.(ResolverFlag)
+	if !ok {
+		return false
+	}
+	for _, name := range rf.GetDisableResolvers() {
+		if name == resolverName(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionalFlag is the interface implemented by flags that can be required depending on the value of another flag.
+type ConditionalFlag interface {
+	Flag
+
+	// GetRequiredIf returns the condition for when the flag is required, either as "name=value" (required when the
+	// flag with the given name has the value) or "name" (required when the flag with the given name is set). The
+	// condition is evaluated after the flags have been resolved.
+	GetRequiredIf() string
+}
+
+// requiredIf returns the condition of a ConditionalFlag, split into the name of the flag and its value (if any).
+func requiredIf(f Flag) (name, value string, hasValue bool) {
+	if cf, ok := f.(ConditionalFlag); ok {
+		name, value, hasValue = strings.Cut(cf.GetRequiredIf(), "=")
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(value), hasValue
+}
+
+// missingConditionalFlags returns the conditionally required flags (see ConditionalFlag) that were neither given in
+// the arguments nor resolved, given the sources returned by resolveMissingFlags.
+func (c *Command) missingConditionalFlags(flags []Flag, sources map[string]string) []string {
+	var missing []string
+	for _, f := range flags {
+		name, value, hasValue := requiredIf(f)
+		if name == "" || sources[f.GetName()] != "" || c.isRequired(f) {
+			continue
+		}
+		pf := c.fs.Lookup(name)
+		if pf == nil {
+			continue
+		}
+		if (hasValue && pf.Value.String() == value) || (!hasValue && sources[name] != "") {
+			missing = append(missing, f.GetName())
+		}
+	}
+	return missing
+}
+
+// PlaceholderFlag is the interface implemented by flags that can set the placeholder for their value in usage texts.
+type PlaceholderFlag interface {
+	Flag
+
+	// GetPlaceholder returns the placeholder that is shown instead of the type of the value, e.g. "<PATH>" to show
+	// "--file <PATH>" instead of "--file string". The placeholder is not used for flags that do not take a value.
+	GetPlaceholder() string
+}
+
+// DefaultTextFlag is the interface implemented by flags that can change how their default value is shown in usage
+// texts and docs.
+type DefaultTextFlag interface {
+	Flag
+
+	// GetDefaultText returns the text that is shown instead of the default value, e.g. "$HOME/.config/app" instead
+	// of the path that it expands to.
+	GetDefaultText() string
+
+	// IsDefaultInUsageDisabled returns true if the default value should not be shown.
+	IsDefaultInUsageDisabled() bool
+}
+
+// usageValue wraps a pflag.Value to change how it is shown in usage texts.
+type usageValue struct {
+	pflag.Value
+	placeholder string
+	def         string
+}
+
+// Type implements pflag.Value.
+func (v *usageValue) Type() string {
+	if v.placeholder != "" {
+		return v.placeholder
+	}
+	return v.Value.Type()
+}
+
+// String implements pflag.Value. It returns the default value to show in usage texts (or an empty string if it
+// should not be shown), since pflag uses it to decide if the default value is the zero value.
+func (v *usageValue) String() string {
+	return v.def
+}
+
+// isZeroDefault returns true if the default value of the flag is the zero value of its type, in which case it is not
+// shown in usage texts.
+func isZeroDefault(pf *pflag.Flag) bool {
+	if pf.Value.Type() == "string" {
+		return pf.DefValue == ""
+	}
+	switch pf.DefValue {
+	case "", "0", "0s", "false", "[]", "<nil>":
+		return true
+	}
+	return false
+}
+
+// SecretFlag is the interface implemented by flags that can hold secrets. The values of secret flags are masked when
+// they are printed by the framework (e.g. by the env command).
+type SecretFlag interface {
+	Flag
+
+	// IsSecret returns true if the value of the flag is a secret.
+	IsSecret() bool
+}
+
+// GatedFlag is the interface implemented by flags that can be put behind a feature gate (see Options.FeatureGates).
+type GatedFlag interface {
+	Flag
+
+	// GetFeatureGate returns the name of the feature gate for the flag, or an empty string if it is not gated.
+	GetFeatureGate() string
+}
+
+// AnnotatedFlag is the interface implemented by flags with annotations, i.e. arbitrary metadata used by extensions
+// (e.g. doc generators or policy tooling). The annotations are not used by this package, except for including them in
+// the Spec.
+type AnnotatedFlag interface {
+	Flag
+
+	// GetAnnotations returns the annotations of the flag.
+	GetAnnotations() map[string]string
+}
+
 // FlagResolver is the interface implemented by custom flag resolvers.
 type FlagResolver interface {
 	Resolve(Flag) (string, bool)
 }
 
+// FlagSource can be implemented by a FlagResolver to describe where the value of a resolved flag came from, e.g.
+// "$AWS_REGION" for an environment variable. The source is shown by the env command.
+type FlagSource interface {
+	Source(Flag) string
+}
+
 // EnvVarResolver implements FlagResolver by resolving variables from the environment.
-type EnvVarResolver struct{}
+type EnvVarResolver struct {
+	// LookupEnv (optional) looks up environment variables, and defaults to os.LookupEnv (or the Options.Environ of
+	// the command, when the resolver is one of the Options.Resolvers).
+	LookupEnv func(key string) (string, bool)
+
+	decorate func(string) string // Options.EnvVarDecorator, set when binding the resolver to a command.
+}
 
 // Resolve implements FlagResolver.
-func (*EnvVarResolver) Resolve(flag Flag) (string, bool) {
+func (r *EnvVarResolver) Resolve(flag Flag) (string, bool) {
+	_, v, found := r.lookup(flag)
+	return v, found
+}
+
+// Name implements NamedResolver.
+func (*EnvVarResolver) Name() string {
+	return "env"
+}
+
+// Source implements FlagSource.
+func (r *EnvVarResolver) Source(flag Flag) string {
+	k, _, _ := r.lookup(flag)
+	if r.decorate != nil {
+		return r.decorate(k)
+	}
+	return defaultEnvVarDecorator(k)
+}
+
+// lookup returns the name and value of the first environment variable of the flag that is set.
+func (r *EnvVarResolver) lookup(flag Flag) (string, string, bool) {
+	lookupEnv := r.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
 	for _, k := range flag.GetEnvVar() {
-		v, found := os.LookupEnv(strings.TrimPrefix(k, "$"))
+		v, found := lookupEnv(envVarName(k))
 		if found {
-			return v, found
+			return envVarName(k), v, found
+		}
+	}
+	return "", "", false
+}
+
+// envVarConflicts returns the environment variables of the flag that are set to a different value than the first
+// one that is set, which is the one used by EnvVarResolver.
+func envVarConflicts(flag Flag, lookupEnv func(string) (string, bool)) (used string, conflicts []string) {
+	var value string
+	for _, k := range flag.GetEnvVar() {
+		v, found := lookupEnv(envVarName(k))
+		switch {
+		case !found:
+		case used == "":
+			used, value = envVarName(k), v
+		case v != value:
+			conflicts = append(conflicts, envVarName(k))
 		}
 	}
-	return "", false
+	return used, conflicts
 }
 
 // ResolveMissingFlags iterates over all missing flags in the given pflag.FlagSet and applies each FlagResolver in turn
 // until the the flag is resolved. An error is returned if we are unable to set the flag to the resolved value, or if
 // a required Flag has missing values after applying all resolvers.
 func ResolveMissingFlags(fs *pflag.FlagSet, flags []Flag, resolvers ...FlagResolver) error {
+	_, missing, err := resolveMissingFlags(fs, flags, resolvers, Flag.IsRequired, nil)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required flags %v", missing)
+	}
+	return nil
+}
+
+// resolveMissingFlags implements ResolveMissingFlags, and returns the sources of the flags that were resolved (see
+// FlagSource) along with the (sorted) names of the required flags that are missing. The sources are returned even if
+// an error is returned. Flags given in the arguments have the source "arg". The required func decides if a flag is
+// required, and references to other flags and environment variables in the resolved values are expanded using
+// expandEnv to look up the environment variables, unless it is nil (see Options.ExpandResolvedValues).
+func resolveMissingFlags(fs *pflag.FlagSet, flags []Flag, resolvers []FlagResolver, required func(Flag) bool, expandEnv func(string) (string, bool)) (map[string]string, []string, error) {
 	var (
 		missingFlags []string
 		resolverErr  error
+		sources      = make(map[string]string)
+		resolved     []resolvedValue
 	)
 
-	fs.VisitAll(func(f *pflag.Flag) {
+	for _, flag := range flags {
+		f := fs.Lookup(flag.GetName())
+		if f == nil {
+			continue
+		}
 		if f.Changed {
-			return // Flag has been set via commandline
+			sources[f.Name] = "arg"
 		}
-		for _, flag := range flags {
-			if flag.GetName() != f.Name {
+		sf, isSlice := flag.(SliceFlag)
+		if f.Changed && !(isSlice && sf.IsAppendResolved()) {
+			continue // Flag has been set via commandline
+		}
+		var (
+			found bool
+			value string
+		)
+		for _, resolver := range resolvers {
+			if resolverDisabled(flag, resolver) {
 				continue
 			}
-			var (
-				found bool
-				value string
-			)
-			for _, resolver := range resolvers {
-				value, found = resolver.Resolve(flag)
-				if found {
-					err := f.Value.Set(value)
-					if err != nil {
-						resolverErr = err
-					}
-					break // Flag was resolved
-				}
+			value, found = resolver.Resolve(flag)
+			if found {
+				resolved = append(resolved, resolvedValue{flag: flag, pf: f, value: value})
+				sources[f.Name] = joinSources(sources[f.Name], resolverSource(resolver, flag))
+				break // Flag was resolved
 			}
-			if !found && flag.IsRequired() {
-				missingFlags = append(missingFlags, flag.GetName())
+		}
+		if !found && !f.Changed && required(flag) {
+			missingFlags = append(missingFlags, flag.GetName())
+		}
+	}
+
+	// The values are set once all flags are resolved, so that references can be expanded regardless of the order.
+	var expander *valueExpander
+	if expandEnv != nil {
+		expander = newValueExpander(fs, resolved, expandEnv)
+	}
+	for _, r := range resolved {
+		value := r.value
+		if expander != nil {
+			v, err := expander.expand(r.flag.GetName())
+			if err != nil {
+				if resolverErr == nil {
+					resolverErr = err
+				}
+				continue
 			}
+			value = v
+		}
+		if err := setResolvedValue(r.pf, r.flag, value); err != nil && resolverErr == nil {
+			resolverErr = err
+		}
+	}
+	sort.Strings(missingFlags)
+	return sources, missingFlags, resolverErr
+}
+
+// resolvedValue is a value returned by a FlagResolver, which has not been set yet.
+type resolvedValue struct {
+	flag  Flag
+	pf    *pflag.Flag
+	value string
+}
+
+// resolverSource returns the source of a flag resolved by the resolver.
+func resolverSource(resolver FlagResolver, flag Flag) string {
+	if s, ok := resolver.(FlagSource); ok {
+		return s.Source(flag)
+	}
+	return fmt.Sprintf("%T", resolver)
+}
+
+// joinSources joins the source of values given in the arguments with the source of values that were appended by a
+// resolver (see SliceFlag.IsAppendResolved).
+func joinSources(arg, resolved string) string {
+	if arg == "" {
+		return resolved
+	}
+	return arg + ", " + resolved
+}
+
+// RepeatPolicy decides what happens when a (non-slice) flag is given multiple times on the command line.
+type RepeatPolicy int
+
+const (
+	// RepeatLastWins uses the last value given for the flag.
+	RepeatLastWins RepeatPolicy = iota
+
+	// RepeatFirstWins uses the first value given for the flag, and ignores the rest.
+	RepeatFirstWins
+
+	// RepeatError returns an error if the flag is given more than once.
+	RepeatError
+)
+
+// applyRepeatPolicy wraps the value of the flag to enforce the RepeatPolicy.
+func applyRepeatPolicy(fs *pflag.FlagSet, name string, policy RepeatPolicy) {
+	if policy == RepeatLastWins {
+		return
+	}
+	f := fs.Lookup(name)
+	f.Value = &repeatValue{Value: f.Value, policy: policy}
+}
+
+// repeatValue implements pflag.Value and enforces a RepeatPolicy.
+type repeatValue struct {
+	pflag.Value
+	policy RepeatPolicy
+	set    bool
+	tr     func(string) string // Translates the error, set by the command when parsing.
+}
+
+// Set implements pflag.Value.
+func (v *repeatValue) Set(s string) error {
+	if v.set {
+		if v.policy == RepeatError {
+			return errors.New(translate(v.tr, "flag can only be specified once"))
 		}
-	})
-	if resolverErr != nil {
-		return resolverErr
+		return nil
 	}
-	if len(missingFlags) > 0 {
-		return fmt.Errorf("missing required flags %v", missingFlags)
+	v.set = true
+	return v.Value.Set(s)
+}
+
+// sliceValue is implemented by the pflag.Value of slice flags.
+type sliceValue interface {
+	Append(string) error
+	Replace([]string) error
+}
+
+// setResolvedValue sets the value of the flag to a value returned by a FlagResolver. Values for a SliceFlag are split
+// by its delimiter (if any), and appended to existing values if the flag has been set on the command line.
+func setResolvedValue(f *pflag.Flag, flag Flag, value string) error {
+	sf, ok := flag.(SliceFlag)
+	if !ok || sf.GetDelimiter() == "" {
+		return f.Value.Set(value) // Slice values append when the flag has been changed.
+	}
+	sv, ok := f.Value.(sliceValue)
+	if !ok {
+		return f.Value.Set(value)
+	}
+	values := splitEscaped(value, sf.GetDelimiter())
+	if !f.Changed {
+		return sv.Replace(values)
+	}
+	for _, v := range values {
+		if err := sv.Append(v); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// splitEscaped splits s by the delimiter, unless the delimiter is escaped by a backslash (e.g. `a\,b`). A literal
+// backslash can be escaped by another backslash.
+func splitEscaped(s, delimiter string) []string {
+	var (
+		values  []string
+		current strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\\' || strings.HasPrefix(s[i+1:], delimiter)):
+			if s[i+1] == '\\' {
+				current.WriteByte('\\')
+				i++
+			} else {
+				current.WriteString(delimiter)
+				i += len(delimiter)
+			}
+		case strings.HasPrefix(s[i:], delimiter):
+			values = append(values, current.String())
+			current.Reset()
+			i += len(delimiter) - 1
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+	return append(values, current.String())
+}
+
+// defaultEnvVarDecorator is the default Options.EnvVarDecorator.
+func defaultEnvVarDecorator(name string) string {
+	if runtime.GOOS == "windows" {
+		return "%" + name + "%"
+	}
+	return "$" + name
+}
+
+// envVarName returns the name of an environment variable without the "$NAME" or "%NAME%" decorations, so that both
+// conventions can be used for Flag.EnvVar on all platforms.
+func envVarName(k string) string {
+	if len(k) > 2 && strings.HasPrefix(k, "%") && strings.HasSuffix(k, "%") {
+		return k[1 : len(k)-1]
+	}
+	return strings.TrimPrefix(k, "$")
+}
+
+// decorateEnvVar formats the name of the environment variable using the EnvVarDecorator.
+func (opts *Options) decorateEnvVar(name string) string {
+	if opts.EnvVarDecorator != nil {
+		return opts.EnvVarDecorator(name)
+	}
+	return defaultEnvVarDecorator(name)
+}
+
+// usageWithEnvVar appends the environment variables to the usage of a flag, using the default decorator (the usage
+// texts of the command use the Options.EnvVarDecorator instead).
 func usageWithEnvVar(usage string, vars []string) string {
+	return decorateUsage(usage, vars, defaultEnvVarDecorator)
+}
+
+// decorateUsage appends the environment variables to the usage of a flag, formatted with decorate.
+func decorateUsage(usage string, vars []string, decorate func(string) string) string {
 	if len(vars) == 0 {
 		return usage
 	}
-	prefix, suffix := "$", ""
-	if runtime.GOOS == "windows" {
-		prefix, suffix = "%", "%"
-	}
 	var env []string
 	for _, v := range vars {
-		env = append(env, prefix+v+suffix)
+		env = append(env, decorate(envVarName(v)))
 	}
 	return fmt.Sprintf("%s [%s]", usage, strings.Join(env, ", "))
 }
 
 func splitFlagName(name string) (longName string, shortName string) {
-	splits := strings.Split(name, ",")
-	switch len(splits) {
-	default:
+	longName, shortName, _ = strings.Cut(name, ",")
+	if strings.Contains(shortName, ",") {
 		panic(fmt.Errorf("invalid variable name: %s", name))
-	case 2:
-		shortName = splits[1]
-		fallthrough
-	case 1:
-		longName = splits[0]
 	}
 	return strings.TrimSpace(longName), strings.TrimSpace(shortName)
 }
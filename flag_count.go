@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+var _ Flag = &CountFlag{}
+
+// CountFlag is used to define a pflag.FlagSet.CountP flag, which counts the number of times it is specified (e.g.
+// -vvv sets it to 3).
+type CountFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   []string
+	Value    int
+	Required bool
+}
+
+// Apply implements Flag.
+func (f *CountFlag) Apply(fs *pflag.FlagSet) {
+	// CountVarP always defaults to zero, so the Value is restored as the default afterwards.
+	value := f.Value
+	fs.CountVarP(&f.Value, f.GetName(), f.GetShorthand(), usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	f.Value = value
+	fs.Lookup(f.GetName()).DefValue = strconv.Itoa(value)
+}
+
+// GetName implements Flag.
+func (f *CountFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *CountFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *CountFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *CountFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// IsRequired implements Flag.
+func (f *CountFlag) IsRequired() bool {
+	return f.Required
+}
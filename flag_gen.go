@@ -4,6 +4,7 @@ package main
 
 import (
 	"os"
+	"strings"
 	"text/template"
 )
 
@@ -17,19 +18,32 @@ func main() {
 	err = flagTemplate.Execute(f, map[string]string{
 		"Bool":          "bool",
 		"BoolSlice":     "[]bool",
+		"BytesBase64":   "[]byte",
+		"BytesHex":      "[]byte",
 		"Duration":      "time.Duration",
 		"DurationSlice": "[]time.Duration",
 		"Int":           "int",
 		"IntSlice":      "[]int",
+		"Int64":         "int64",
+		"Int64Slice":    "[]int64",
 		"String":        "string",
 		"StringSlice":   "[]string",
+		"StringToInt":   "map[string]int",
+		"StringToInt64": "map[string]int64",
 	})
 	if err != nil {
 		panic(err)
 	}
 }
 
-var flagTemplate = template.Must(template.New("").Parse(`package cli
+var flagTemplate = template.Must(template.New("").Funcs(template.FuncMap{
+	"isSlice":    func(name string) bool { return strings.HasSuffix(name, "Slice") },
+	"isDuration": func(name string) bool { return strings.HasPrefix(name, "Duration") },
+	"isMap":      func(name string) bool { return strings.HasPrefix(name, "StringTo") },
+	"isSecret": func(name string) bool {
+		return name == "String" || name == "StringSlice" || strings.HasPrefix(name, "Bytes")
+	},
+}).Parse(`package cli
 
 // Code generated by go generate; DO NOT EDIT.
 
@@ -39,20 +53,91 @@ import (
 	"github.com/spf13/pflag"
 )
 {{ range $name, $type := . }}
+{{- if isSlice $name }}
+var _ SliceFlag = &{{ $name }}Flag{}
+{{- else }}
 var _ Flag = &{{ $name }}Flag{}
+{{- end }}
+{{- if isSecret $name }}
+var _ SecretFlag = &{{ $name }}Flag{}
+{{- end }}
+var _ GatedFlag = &{{ $name }}Flag{}
+var _ AnnotatedFlag = &{{ $name }}Flag{}
+var _ PlaceholderFlag = &{{ $name }}Flag{}
+var _ DefaultTextFlag = &{{ $name }}Flag{}
+var _ ConditionalFlag = &{{ $name }}Flag{}
+var _ ResolverFlag = &{{ $name }}Flag{}
 
 // {{ $name }}Flag is used to define a pflag.FlagSet.{{ $name }}P flag.
+{{- if isDuration $name }}
+// In addition to the units supported by time.ParseDuration, durations can be specified in days ("d") and weeks
+// ("w"), e.g. "1d12h".
+{{- end }}
+{{- if isSlice $name }}
 type {{ $name }}Flag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    {{ $type }}
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 {{ $type }}
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
+{{- if isSecret $name }}
+	Secret                bool
+{{- end }}
 }
+{{- else if isMap $name }}
+type {{ $name }}Flag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 {{ $type }}
+	Required              bool
+	RequiredIf            string
+	FeatureGate           string
+	Annotations           map[string]string
+}
+{{- else }}
+type {{ $name }}Flag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 {{ $type }}
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+{{- if isSecret $name }}
+	Secret                bool
+{{- end }}
+}
+{{- end }}
 
 // Apply implements Flag.
 func (f *{{ $name }}Flag) Apply(fs *pflag.FlagSet) {
 	fs.{{ $name }}VarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+{{- if isDuration $name }}
+	applyDurationUnits(fs, f.GetName())
+{{- end }}
+{{- if not (or (isSlice $name) (isMap $name)) }}
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+{{- end }}
 }
 
 // GetName implements Flag.
@@ -77,9 +162,63 @@ func (f *{{ $name }}Flag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *{{ $name }}Flag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *{{ $name }}Flag) IsRequired() bool {
 	return f.Required
 }
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *{{ $name }}Flag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *{{ $name }}Flag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *{{ $name }}Flag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *{{ $name }}Flag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *{{ $name }}Flag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *{{ $name }}Flag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+{{- if isSlice $name }}
+
+// GetDelimiter implements SliceFlag.
+func (f *{{ $name }}Flag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *{{ $name }}Flag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+{{- end }}
+{{- if isSecret $name }}
+
+// IsSecret implements SecretFlag.
+func (f *{{ $name }}Flag) IsSecret() bool {
+	return f.Secret
+}
+{{- end }}
 {{ end -}}
 `))
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"reflect"
+)
+
+// FlagGroup is a named set of flags that can be shared between commands. Each command that uses the group gets its
+// own copy of the flags, so values do not bleed between commands. The flags of a group are listed in a separate
+// section (using the Name) in the usage of a command.
+type FlagGroup struct {
+	Name  string
+	Flags []Flag
+}
+
+// copyFlag returns a copy of a flag implemented as a pointer to a struct (like all flag types in this package),
+// including copies of any slices and maps in its exported fields. Other implementations of Flag are returned as-is.
+func copyFlag(f Flag) Flag {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return f
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+
+	for i := 0; i < c.Elem().NumField(); i++ {
+		field := c.Elem().Field(i)
+		if field.IsZero() || !field.CanSet() {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Slice:
+			s := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			reflect.Copy(s, field)
+			field.Set(s)
+		case reflect.Map:
+			m := reflect.MakeMapWithSize(field.Type(), field.Len())
+			for it := field.MapRange(); it.Next(); {
+				m.SetMapIndex(it.Key(), it.Value())
+			}
+			field.Set(m)
+		}
+	}
+	return c.Interface().(Flag)
+}
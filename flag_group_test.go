@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestFlagGroup(t *testing.T) {
+	aws := cli.FlagGroup{
+		Name: "AWS",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "region, r",
+				Usage: "AWS Region to target",
+				Value: "eu-west-1",
+			},
+			&cli.StringSliceFlag{
+				Name:  "instance, i",
+				Usage: "An instance to target",
+				Value: []string{"default"},
+			},
+		},
+	}
+
+	var regions, instances []interface{}
+	exec := func(c *cli.Context) error {
+		region, err := c.GetString("region")
+		eq(t, nil, err)
+		instance, err := c.GetStringSlice("instance")
+		eq(t, nil, err)
+		regions, instances = append(regions, region), append(instances, instance)
+		return nil
+	}
+
+	c := cli.Command{
+		Usage: "root [command]",
+		Subcommands: []*cli.Command{
+			{Usage: "first [flags]", FlagGroups: []cli.FlagGroup{aws}, Exec: exec},
+			{Usage: "second [flags]", FlagGroups: []cli.FlagGroup{aws}, Exec: exec},
+		},
+		Opts: cli.Options{
+			ErrWriter: os.Stdout,
+		},
+	}
+
+	eq(t, nil, c.Execute([]string{"first", "-r", "eu-north-1", "-i", "i-1"}))
+	eq(t, nil, c.Execute([]string{"second"}))
+	eq(t, []interface{}{"eu-north-1", "eu-west-1"}, regions)
+	eq(t, []interface{}{[]string{"i-1"}, []string{"default"}}, instances)
+	eq(t, "eu-west-1", aws.Flags[0].(*cli.StringFlag).Value)
+}
+
+func Example_flagGroups() {
+	c := cli.Command{
+		Usage: "list [flags]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "all, a",
+				Usage: "List all instances",
+			},
+		},
+		FlagGroups: []cli.FlagGroup{
+			{
+				Name: "AWS",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "region, r",
+						Usage: "AWS Region to target",
+					},
+				},
+			},
+		},
+		Exec: func(c *cli.Context) error {
+			return nil
+		},
+		Opts: cli.Options{
+			ErrWriter: os.Stdout,
+		},
+	}
+	if err := c.Execute([]string{"--help"}); err != nil {
+		panic(err)
+	}
+	// Output:
+	//
+	// Usage:
+	//   list [flags]
+	//
+	// Flags:
+	//   -a, --all   List all instances
+	//
+	// AWS Flags:
+	//   -r, --region string   AWS Region to target
+}
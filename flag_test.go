@@ -1,9 +1,14 @@
 package cli_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/itsdalmo/cli"
 )
@@ -98,3 +103,721 @@ func TestFlagParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestRepeatPolicy(t *testing.T) {
+	tests := []struct {
+		description    string
+		policy         cli.RepeatPolicy
+		expectedRegion string
+		expectedErr    error
+	}{
+		{
+			description:    "last wins",
+			policy:         cli.RepeatLastWins,
+			expectedRegion: "us-east-1",
+		},
+		{
+			description:    "first wins",
+			policy:         cli.RepeatFirstWins,
+			expectedRegion: "eu-north-1",
+		},
+		{
+			description: "error",
+			policy:      cli.RepeatError,
+			expectedErr: errors.New(`invalid argument "us-east-1" for "-r, --region" flag: flag can only be specified once`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "echo [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "region, r",
+						Usage:    "AWS Region to target",
+						Repeated: tc.policy,
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					region, err := c.GetString("region")
+					eq(t, nil, err)
+					eq(t, tc.expectedRegion, region)
+					return nil
+				},
+			}
+			err := c.Execute([]string{"--region", "eu-north-1", "-r", "us-east-1"})
+			if tc.expectedErr == nil {
+				eq(t, nil, err)
+				return
+			}
+			var invalid *cli.ErrInvalidFlagValue
+			eq(t, true, errors.As(err, &invalid))
+			eq(t, tc.expectedErr.Error(), invalid.Error())
+		})
+	}
+}
+
+func TestSliceFlagResolution(t *testing.T) {
+	tests := []struct {
+		description    string
+		args           []string
+		env            string
+		delimiter      string
+		appendResolved bool
+		expected       []string
+	}{
+		{
+			description: "defaults to comma separated values",
+			env:         "a,b",
+			expected:    []string{"a", "b"},
+		},
+		{
+			description: "custom delimiter",
+			env:         "SELECT a, b FROM t;https://example.com/?x=1,2",
+			delimiter:   ";",
+			expected:    []string{"SELECT a, b FROM t", "https://example.com/?x=1,2"},
+		},
+		{
+			description: "escaped delimiter",
+			env:         `a\,b,c\\`,
+			delimiter:   ",",
+			expected:    []string{"a,b", `c\`},
+		},
+		{
+			description: "command line replaces resolved values",
+			args:        []string{"-q", "cli"},
+			env:         "a;b",
+			delimiter:   ";",
+			expected:    []string{"cli"},
+		},
+		{
+			description:    "append resolved values",
+			args:           []string{"-q", "cli"},
+			env:            "a;b",
+			delimiter:      ";",
+			appendResolved: true,
+			expected:       []string{"cli", "a", "b"},
+		},
+		{
+			description:    "append resolved values without delimiter",
+			args:           []string{"-q", "cli"},
+			env:            "a,b",
+			appendResolved: true,
+			expected:       []string{"cli", "a", "b"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "query [flags]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:           "query, q",
+						Usage:          "Queries to run",
+						EnvVar:         []string{"QUERIES"},
+						Delimiter:      tc.delimiter,
+						AppendResolved: tc.appendResolved,
+					},
+				},
+				Exec: func(c *cli.Context) error {
+					queries, err := c.GetStringSlice("query")
+					eq(t, nil, err)
+					eq(t, tc.expected, queries)
+					return nil
+				},
+			}
+			os.Setenv("QUERIES", tc.env)
+			defer os.Unsetenv("QUERIES")
+
+			eq(t, nil, c.Execute(tc.args))
+		})
+	}
+}
+
+func TestFlagValueIsolation(t *testing.T) {
+	region := &cli.StringFlag{
+		Name:  "region, r",
+		Usage: "AWS Region to target",
+		Value: "eu-west-1",
+	}
+
+	var got []string
+	exec := func(c *cli.Context) error {
+		v, err := c.GetString("region")
+		eq(t, nil, err)
+		got = append(got, v)
+		return nil
+	}
+	c := cli.Command{
+		Usage: "root [command]",
+		Subcommands: []*cli.Command{
+			{Usage: "first [flags]", Flags: []cli.Flag{region}, Exec: exec},
+			{Usage: "second [flags]", Flags: []cli.Flag{region}, Exec: exec},
+		},
+	}
+
+	eq(t, nil, c.Execute([]string{"first", "--region", "eu-north-1"}))
+	eq(t, nil, c.Execute([]string{"second"}))
+	eq(t, nil, c.Execute([]string{"first"}))
+	eq(t, []string{"eu-north-1", "eu-west-1", "eu-west-1"}, got)
+	eq(t, "eu-west-1", region.Value)
+}
+
+func TestUsageDoesNotModifyEnvVar(t *testing.T) {
+	os.Setenv("CLI_TEST_REGION", "eu-west-1")
+	defer os.Unsetenv("CLI_TEST_REGION")
+
+	flag := &cli.StringFlag{
+		Name:   "region",
+		EnvVar: []string{"CLI_TEST_REGION"},
+	}
+	c := cli.Command{
+		Usage: "echo [flags]",
+		Flags: []cli.Flag{flag},
+		Opts:  cli.Options{ErrWriter: io.Discard},
+		Exec: func(c *cli.Context) error {
+			region, err := c.GetString("region")
+			eq(t, nil, err)
+			eq(t, "eu-west-1", region)
+			return nil
+		},
+	}
+	for i := 0; i < 2; i++ {
+		eq(t, nil, c.Execute([]string{"--help"}))
+	}
+	eq(t, []string{"CLI_TEST_REGION"}, flag.EnvVar)
+	eq(t, nil, c.Execute([]string{}))
+}
+
+func TestEnvVarDecoration(t *testing.T) {
+	for _, envVar := range []string{"CLI_TEST_REGION", "$CLI_TEST_REGION", "%CLI_TEST_REGION%"} {
+		envVar := envVar
+		t.Run(envVar, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "echo [flags]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region", Usage: "AWS region", EnvVar: []string{envVar}}},
+				Opts: cli.Options{
+					ErrWriter:       &b,
+					Environ:         []string{"CLI_TEST_REGION=eu-west-1"},
+					EnvVarDecorator: func(name string) string { return "%" + name + "%" },
+				},
+				Exec: func(c *cli.Context) error {
+					region, err := c.GetString("region")
+					eq(t, nil, err)
+					eq(t, "eu-west-1", region)
+					eq(t, "%CLI_TEST_REGION%", c.FlagSource("region"))
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute([]string{}))
+			eq(t, nil, c.Execute([]string{"--help"}))
+			eq(t, true, strings.Contains(b.String(), "AWS region [%CLI_TEST_REGION%]"))
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		var b bytes.Buffer
+		c := cli.Command{
+			Usage: "echo [flags]",
+			Flags: []cli.Flag{&cli.StringFlag{Name: "region", Usage: "AWS region", EnvVar: []string{"CLI_TEST_REGION"}}},
+			Opts:  cli.Options{ErrWriter: &b, Environ: []string{}},
+			Exec:  func(c *cli.Context) error { return nil },
+		}
+		eq(t, nil, c.Execute([]string{"--help"}))
+		eq(t, runtime.GOOS != "windows", strings.Contains(b.String(), "AWS region [$CLI_TEST_REGION]"))
+	})
+}
+
+func TestInt64Flags(t *testing.T) {
+	os.Setenv("CLI_TEST_EPOCHS", "1700000000000;-4102444800000")
+	defer os.Unsetenv("CLI_TEST_EPOCHS")
+
+	c := cli.Command{
+		Usage: "echo [flags]",
+		Flags: []cli.Flag{
+			&cli.Int64Flag{Name: "id", Usage: "ID of the resource", Required: true},
+			&cli.Int64SliceFlag{Name: "epoch", Usage: "Epochs in milliseconds", EnvVar: []string{"CLI_TEST_EPOCHS"}, Delimiter: ";"},
+		},
+		Exec: func(c *cli.Context) error {
+			id, err := c.GetInt64("id")
+			eq(t, nil, err)
+			eq(t, int64(9007199254740993), id)
+
+			epochs, err := c.GetInt64Slice("epoch")
+			eq(t, nil, err)
+			eq(t, []int64{1700000000000, -4102444800000}, epochs)
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--id", "9007199254740993"}))
+
+	var invalid *cli.ErrInvalidFlagValue
+	eq(t, true, errors.As(c.Execute([]string{"--id", "9223372036854775808"}), &invalid))
+	eq(t, "id", invalid.Name)
+}
+
+func TestCountFlagValue(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expected    int
+	}{
+		{
+			description: "defaults to value",
+			args:        []string{},
+			expected:    2,
+		},
+		{
+			description: "counts from value",
+			args:        []string{"-vv"},
+			expected:    4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "echo [flags]",
+				Flags: []cli.Flag{&cli.CountFlag{Name: "verbose, v", Usage: "Verbosity", Value: 2}},
+				Exec: func(c *cli.Context) error {
+					verbose, err := c.GetCount("verbose")
+					eq(t, nil, err)
+					eq(t, tc.expected, verbose)
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+		})
+	}
+}
+
+func TestStringToIntFlags(t *testing.T) {
+	os.Setenv("CLI_TEST_LIMITS", "cpu=4000000000,memory=8589934592")
+	defer os.Unsetenv("CLI_TEST_LIMITS")
+
+	weight := &cli.StringToIntFlag{Name: "weight, w", Usage: "Weight per region", Value: map[string]int{"us-east-1": 1}}
+	var got []map[string]int
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			weight,
+			&cli.StringToInt64Flag{Name: "limit", Usage: "Resource limits", EnvVar: []string{"CLI_TEST_LIMITS"}},
+		},
+		Exec: func(c *cli.Context) error {
+			weights, err := c.GetStringToInt("weight")
+			eq(t, nil, err)
+			got = append(got, weights)
+
+			limits, err := c.GetStringToInt64("limit")
+			eq(t, nil, err)
+			eq(t, map[string]int64{"cpu": 4000000000, "memory": 8589934592}, limits)
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--weight", "us-east-1=10", "-w", "eu-west-1=3"}))
+	eq(t, nil, c.Execute([]string{}))
+	eq(t, []map[string]int{{"us-east-1": 10, "eu-west-1": 3}, {"us-east-1": 1}}, got)
+	eq(t, map[string]int{"us-east-1": 1}, weight.Value)
+
+	var invalid *cli.ErrInvalidFlagValue
+	eq(t, true, errors.As(c.Execute([]string{"--weight", "us-east-1=high"}), &invalid))
+	eq(t, "weight", invalid.Name)
+}
+
+func TestBytesFlags(t *testing.T) {
+	c := cli.Command{
+		Usage: "verify [flags]",
+		Flags: []cli.Flag{
+			&cli.BytesBase64Flag{Name: "key", Usage: "Signing key", Secret: true},
+			&cli.BytesHexFlag{Name: "checksum", Usage: "Expected SHA-256 checksum"},
+		},
+		Exec: func(c *cli.Context) error {
+			key, err := c.GetBytesBase64("key")
+			eq(t, nil, err)
+			eq(t, []byte("secret"), key)
+
+			checksum, err := c.GetBytesHex("checksum")
+			eq(t, nil, err)
+			eq(t, []byte{0xde, 0xad, 0xbe, 0xef}, checksum)
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--key", "c2VjcmV0", "--checksum", "DEADBEEF"}))
+
+	for _, args := range [][]string{{"--key", "not base64"}, {"--checksum", "xyz"}} {
+		var invalid *cli.ErrInvalidFlagValue
+		eq(t, true, errors.As(c.Execute(args), &invalid))
+		eq(t, strings.TrimPrefix(args[0], "--"), invalid.Name)
+	}
+}
+
+func TestDurationUnits(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected time.Duration
+		slice    []time.Duration
+	}{
+		{args: []string{"--retention", "2d"}, expected: 48 * time.Hour, slice: []time.Duration{}},
+		{args: []string{"--retention", "1w"}, expected: 168 * time.Hour, slice: []time.Duration{}},
+		{args: []string{"--retention", "1d12h"}, expected: 36 * time.Hour, slice: []time.Duration{}},
+		{args: []string{"--retention", "1.5d"}, expected: 36 * time.Hour, slice: []time.Duration{}},
+		{args: []string{"--retention", "90m"}, expected: 90 * time.Minute, slice: []time.Duration{}},
+		{args: []string{"--expiry", "1d,2w", "--expiry", "30s"}, expected: -1, slice: []time.Duration{24 * time.Hour, 336 * time.Hour, 30 * time.Second}},
+	}
+
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			c := cli.Command{
+				Usage: "cleanup [flags]",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{Name: "retention", Value: -1},
+					&cli.DurationSliceFlag{Name: "expiry"},
+				},
+				Exec: func(c *cli.Context) error {
+					retention, err := c.GetDuration("retention")
+					eq(t, nil, err)
+					eq(t, tc.expected, retention)
+
+					expiry, err := c.GetDurationSlice("expiry")
+					eq(t, nil, err)
+					eq(t, tc.slice, expiry)
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+		})
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "upload [flags]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "file, f", Usage: "File to upload", Placeholder: "<PATH>"},
+			&cli.StringFlag{Name: "region", Usage: "AWS region", Placeholder: "<AWS_REGION>", Value: "eu-west-1"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Print what would be uploaded", Placeholder: "<IGNORED>"},
+			&cli.IntFlag{Name: "parts", Usage: "Number of parts"},
+		},
+		Opts: cli.Options{ErrWriter: &b},
+		Exec: func(c *cli.Context) error {
+			file, err := c.GetString("file")
+			eq(t, nil, err)
+			eq(t, "a.txt", file)
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--help"}))
+
+	expected := `Usage:
+  upload [flags]
+
+Flags:
+      --dry-run               Print what would be uploaded
+  -f, --file <PATH>           File to upload
+      --parts int             Number of parts
+      --region <AWS_REGION>   AWS region (default eu-west-1)
+
+`
+	eq(t, expected, b.String())
+	eq(t, nil, c.Execute([]string{"-f", "a.txt"}))
+}
+
+func TestDefaultInUsage(t *testing.T) {
+	flags := func() []cli.Flag {
+		return []cli.Flag{
+			&cli.StringFlag{Name: "config", Usage: "Config file", Value: "/home/user/.config/app", DefaultText: "$HOME/.config/app"},
+			&cli.IntFlag{Name: "workers", Usage: "Number of workers", Value: 8, DisableDefaultInUsage: true},
+			&cli.IntFlag{Name: "retries", Usage: "Number of retries", Value: 3},
+		}
+	}
+	tests := []struct {
+		description string
+		opts        cli.Options
+		expected    string
+	}{
+		{
+			description: "per flag",
+			expected: `      --config string   Config file (default "$HOME/.config/app")
+      --retries int     Number of retries (default 3)
+      --workers int     Number of workers
+`,
+		},
+		{
+			description: "options",
+			opts:        cli.Options{DisableDefaultInUsage: true},
+			expected: `      --config string   Config file
+      --retries int     Number of retries
+      --workers int     Number of workers
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			tc.opts.ErrWriter = &b
+			c := cli.Command{
+				Usage: "run [flags]",
+				Flags: flags(),
+				Opts:  tc.opts,
+				Exec:  func(c *cli.Context) error { return nil },
+			}
+			eq(t, nil, c.Execute([]string{"--help"}))
+			eq(t, "Usage:\n  run [flags]\n\nFlags:\n"+tc.expected+"\n", b.String())
+		})
+	}
+}
+
+func TestMarkRequiredAndHidden(t *testing.T) {
+	var b bytes.Buffer
+	deploy := &cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "host", Usage: "Remote host"},
+			&cli.StringFlag{Name: "target", Usage: "Deployment target", Required: true},
+		},
+		Exec: func(c *cli.Context) error { return nil },
+	}
+	c := cli.Command{
+		Usage:       "app [command]",
+		Flags:       []cli.Flag{&cli.BoolFlag{Name: "debug", Usage: "Debug logging"}},
+		Subcommands: []*cli.Command{deploy},
+		Opts:        cli.Options{ErrWriter: &b},
+	}
+	eq(t, `flag "debug" is not defined by command "deploy"`, deploy.MarkRequired("debug", true).Error())
+
+	eq(t, nil, deploy.MarkRequired("host", true))
+	eq(t, nil, deploy.MarkRequired("target", false))
+	eq(t, nil, c.MarkHidden("debug", true))
+	eq(t, "parsing command: missing required flags [host]", c.Execute([]string{"deploy"}).Error())
+	eq(t, nil, c.Execute([]string{"deploy", "--host", "example.com"}))
+
+	eq(t, nil, c.Execute([]string{"deploy", "--help"}))
+	eq(t, false, strings.Contains(b.String(), "--debug"))
+
+	eq(t, nil, deploy.MarkRequired("host", false))
+	eq(t, nil, c.MarkHidden("debug", false))
+	eq(t, nil, c.Execute([]string{"deploy"}))
+	eq(t, nil, c.Execute([]string{"deploy", "--help"}))
+	eq(t, true, strings.Contains(b.String(), "--debug"))
+}
+
+func TestRequiredIf(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			description: "condition not met",
+			args:        []string{"--mode", "local"},
+		},
+		{
+			description: "condition met",
+			args:        []string{"--mode", "remote"},
+			expectedErr: "parsing command: missing required flags [host token]",
+		},
+		{
+			description: "condition met by resolved value",
+			env:         map[string]string{"CLI_TEST_MODE": "remote", "CLI_TEST_HOST": "example.com"},
+			expectedErr: "parsing command: missing required flags [token]",
+		},
+		{
+			description: "condition met and flags given",
+			args:        []string{"--mode", "remote", "--host", "example.com", "--token", "secret"},
+		},
+		{
+			description: "flag is set",
+			args:        []string{"--port", "22"},
+			expectedErr: "parsing command: missing required flags [user]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			c := cli.Command{
+				Usage: "connect [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "mode", Value: "local", EnvVar: []string{"CLI_TEST_MODE"}},
+					&cli.StringFlag{Name: "host", EnvVar: []string{"CLI_TEST_HOST"}, RequiredIf: "mode=remote"},
+					&cli.StringFlag{Name: "token", RequiredIf: "mode=remote"},
+					&cli.IntFlag{Name: "port"},
+					&cli.StringFlag{Name: "user", RequiredIf: "port"},
+				},
+				Exec: func(c *cli.Context) error { return nil },
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr == "" {
+				eq(t, nil, err)
+				return
+			}
+			eq(t, tc.expectedErr, err.Error())
+		})
+	}
+
+	t.Run("undefined flag", func(t *testing.T) {
+		c := cli.Command{
+			Usage: "connect [flags]",
+			Flags: []cli.Flag{&cli.StringFlag{Name: "host", RequiredIf: "mode=remote"}},
+			Exec:  func(c *cli.Context) error { return nil },
+		}
+		eq(t, `parsing command: misconfigured command "connect": condition for flag "host" refers to undefined flag "mode"`, c.Execute(nil).Error())
+	})
+}
+
+type staticResolver map[string]string
+
+func (r staticResolver) Resolve(f cli.Flag) (string, bool) {
+	v, ok := r[f.GetName()]
+	return v, ok
+}
+
+func TestResolverErrorIsNotMasked(t *testing.T) {
+	c := cli.Command{
+		Usage:     "get [flags] [resource]",
+		Flags:     []cli.Flag{&cli.IntFlag{Name: "port"}},
+		ValidArgs: []string{"pods", "services"},
+		Opts:      cli.Options{Resolvers: []cli.FlagResolver{staticResolver{"port": "abc"}}},
+		Exec:      func(c *cli.Context) error { return nil },
+	}
+	eq(t, `parsing command: strconv.ParseInt: parsing "abc": invalid syntax`, c.Execute([]string{"nodes"}).Error())
+}
+
+func TestDisableResolvers(t *testing.T) {
+	os.Setenv("CLI_TEST_TOKEN", "from-env")
+	defer os.Unsetenv("CLI_TEST_TOKEN")
+
+	tests := []struct {
+		description string
+		disable     []string
+		expected    string
+	}{
+		{
+			description: "all resolvers",
+			expected:    "from-env",
+		},
+		{
+			description: "disable env",
+			disable:     []string{"env"},
+			expected:    "from-static",
+		},
+		{
+			description: "disable env and unnamed resolver",
+			disable:     []string{"env", "cli_test.staticResolver"},
+			expected:    "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "login [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "token", EnvVar: []string{"CLI_TEST_TOKEN"}, DisableResolvers: tc.disable},
+				},
+				Opts: cli.Options{
+					Resolvers: []cli.FlagResolver{&cli.EnvVarResolver{}, staticResolver{"token": "from-static"}},
+				},
+				Exec: func(c *cli.Context) error {
+					token, err := c.GetString("token")
+					eq(t, nil, err)
+					eq(t, tc.expected, token)
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(nil))
+		})
+	}
+}
+
+func TestResolverPrecedence(t *testing.T) {
+	os.Setenv("CLI_TEST_REGION", "from-env")
+	defer os.Unsetenv("CLI_TEST_REGION")
+
+	tests := []struct {
+		description string
+		args        []string
+		opts        cli.Options
+		expected    string
+	}{
+		{
+			description: "env before appended resolvers",
+			opts:        cli.Options{AppendResolvers: []cli.FlagResolver{staticResolver{"region": "from-config"}}},
+			expected:    "from-env",
+		},
+		{
+			description: "prepended resolvers before env",
+			opts:        cli.Options{PrependResolvers: []cli.FlagResolver{staticResolver{"region": "from-config"}}},
+			expected:    "from-config",
+		},
+		{
+			description: "arguments before resolvers",
+			args:        []string{"--region", "from-arg"},
+			opts:        cli.Options{PrependResolvers: []cli.FlagResolver{staticResolver{"region": "from-config"}}},
+			expected:    "from-arg",
+		},
+		{
+			description: "appended resolvers are used for unresolved flags",
+			opts:        cli.Options{Resolvers: []cli.FlagResolver{}, AppendResolvers: []cli.FlagResolver{staticResolver{"region": "from-config"}}},
+			expected:    "from-config",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "deploy [flags]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region", EnvVar: []string{"CLI_TEST_REGION"}}},
+				Opts:  tc.opts,
+				Exec: func(c *cli.Context) error {
+					region, err := c.GetString("region")
+					eq(t, nil, err)
+					eq(t, tc.expected, region)
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+		})
+	}
+}
+
+type pathResolver struct{}
+
+func (pathResolver) Resolve(f cli.Flag) (string, bool) { return "", false }
+
+func (pathResolver) ResolvePath(path []string, f cli.Flag) (string, bool) {
+	return strings.Join(append(path, f.GetName()), "/"), true
+}
+
+func TestPathResolver(t *testing.T) {
+	var got []string
+	exec := func(c *cli.Context) error {
+		region, err := c.GetString("region")
+		eq(t, nil, err)
+		got = append(got, region)
+		return nil
+	}
+	c := cli.Command{
+		Usage: "app [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region"}},
+		Subcommands: []*cli.Command{
+			{Usage: "deploy", Exec: exec},
+			{Usage: "status", Exec: exec},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{pathResolver{}}},
+	}
+	eq(t, nil, c.Execute([]string{"deploy"}))
+	eq(t, nil, c.Execute([]string{"status"}))
+	eq(t, []string{"app/deploy/region", "app/status/region"}, got)
+}
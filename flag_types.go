@@ -9,19 +9,34 @@ import (
 )
 
 var _ Flag = &BoolFlag{}
+var _ GatedFlag = &BoolFlag{}
+var _ AnnotatedFlag = &BoolFlag{}
+var _ PlaceholderFlag = &BoolFlag{}
+var _ DefaultTextFlag = &BoolFlag{}
+var _ ConditionalFlag = &BoolFlag{}
+var _ ResolverFlag = &BoolFlag{}
 
 // BoolFlag is used to define a pflag.FlagSet.BoolP flag.
 type BoolFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    bool
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 bool
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
 func (f *BoolFlag) Apply(fs *pflag.FlagSet) {
 	fs.BoolVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
 }
 
 // GetName implements Flag.
@@ -46,20 +61,70 @@ func (f *BoolFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *BoolFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *BoolFlag) IsRequired() bool {
 	return f.Required
 }
 
-var _ Flag = &BoolSliceFlag{}
+// GetRequiredIf implements ConditionalFlag.
+func (f *BoolFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *BoolFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *BoolFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *BoolFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *BoolFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *BoolFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+var _ SliceFlag = &BoolSliceFlag{}
+var _ GatedFlag = &BoolSliceFlag{}
+var _ AnnotatedFlag = &BoolSliceFlag{}
+var _ PlaceholderFlag = &BoolSliceFlag{}
+var _ DefaultTextFlag = &BoolSliceFlag{}
+var _ ConditionalFlag = &BoolSliceFlag{}
+var _ ResolverFlag = &BoolSliceFlag{}
 
 // BoolSliceFlag is used to define a pflag.FlagSet.BoolSliceP flag.
 type BoolSliceFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    []bool
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []bool
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
@@ -89,25 +154,288 @@ func (f *BoolSliceFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *BoolSliceFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *BoolSliceFlag) IsRequired() bool {
 	return f.Required
 }
 
+// GetRequiredIf implements ConditionalFlag.
+func (f *BoolSliceFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *BoolSliceFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *BoolSliceFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *BoolSliceFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *BoolSliceFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *BoolSliceFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetDelimiter implements SliceFlag.
+func (f *BoolSliceFlag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *BoolSliceFlag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+
+var _ Flag = &BytesBase64Flag{}
+var _ SecretFlag = &BytesBase64Flag{}
+var _ GatedFlag = &BytesBase64Flag{}
+var _ AnnotatedFlag = &BytesBase64Flag{}
+var _ PlaceholderFlag = &BytesBase64Flag{}
+var _ DefaultTextFlag = &BytesBase64Flag{}
+var _ ConditionalFlag = &BytesBase64Flag{}
+var _ ResolverFlag = &BytesBase64Flag{}
+
+// BytesBase64Flag is used to define a pflag.FlagSet.BytesBase64P flag.
+type BytesBase64Flag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []byte
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+	Secret                bool
+}
+
+// Apply implements Flag.
+func (f *BytesBase64Flag) Apply(fs *pflag.FlagSet) {
+	fs.BytesBase64VarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+}
+
+// GetName implements Flag.
+func (f *BytesBase64Flag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *BytesBase64Flag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *BytesBase64Flag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *BytesBase64Flag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *BytesBase64Flag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *BytesBase64Flag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *BytesBase64Flag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *BytesBase64Flag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *BytesBase64Flag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *BytesBase64Flag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *BytesBase64Flag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *BytesBase64Flag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// IsSecret implements SecretFlag.
+func (f *BytesBase64Flag) IsSecret() bool {
+	return f.Secret
+}
+
+var _ Flag = &BytesHexFlag{}
+var _ SecretFlag = &BytesHexFlag{}
+var _ GatedFlag = &BytesHexFlag{}
+var _ AnnotatedFlag = &BytesHexFlag{}
+var _ PlaceholderFlag = &BytesHexFlag{}
+var _ DefaultTextFlag = &BytesHexFlag{}
+var _ ConditionalFlag = &BytesHexFlag{}
+var _ ResolverFlag = &BytesHexFlag{}
+
+// BytesHexFlag is used to define a pflag.FlagSet.BytesHexP flag.
+type BytesHexFlag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []byte
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+	Secret                bool
+}
+
+// Apply implements Flag.
+func (f *BytesHexFlag) Apply(fs *pflag.FlagSet) {
+	fs.BytesHexVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+}
+
+// GetName implements Flag.
+func (f *BytesHexFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *BytesHexFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *BytesHexFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *BytesHexFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *BytesHexFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *BytesHexFlag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *BytesHexFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *BytesHexFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *BytesHexFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *BytesHexFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *BytesHexFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *BytesHexFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// IsSecret implements SecretFlag.
+func (f *BytesHexFlag) IsSecret() bool {
+	return f.Secret
+}
+
 var _ Flag = &DurationFlag{}
+var _ GatedFlag = &DurationFlag{}
+var _ AnnotatedFlag = &DurationFlag{}
+var _ PlaceholderFlag = &DurationFlag{}
+var _ DefaultTextFlag = &DurationFlag{}
+var _ ConditionalFlag = &DurationFlag{}
+var _ ResolverFlag = &DurationFlag{}
 
 // DurationFlag is used to define a pflag.FlagSet.DurationP flag.
+// In addition to the units supported by time.ParseDuration, durations can be specified in days ("d") and weeks
+// ("w"), e.g. "1d12h".
 type DurationFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    time.Duration
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 time.Duration
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
 func (f *DurationFlag) Apply(fs *pflag.FlagSet) {
 	fs.DurationVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyDurationUnits(fs, f.GetName())
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
 }
 
 // GetName implements Flag.
@@ -132,25 +460,78 @@ func (f *DurationFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *DurationFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *DurationFlag) IsRequired() bool {
 	return f.Required
 }
 
-var _ Flag = &DurationSliceFlag{}
+// GetRequiredIf implements ConditionalFlag.
+func (f *DurationFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *DurationFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *DurationFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *DurationFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *DurationFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *DurationFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+var _ SliceFlag = &DurationSliceFlag{}
+var _ GatedFlag = &DurationSliceFlag{}
+var _ AnnotatedFlag = &DurationSliceFlag{}
+var _ PlaceholderFlag = &DurationSliceFlag{}
+var _ DefaultTextFlag = &DurationSliceFlag{}
+var _ ConditionalFlag = &DurationSliceFlag{}
+var _ ResolverFlag = &DurationSliceFlag{}
 
 // DurationSliceFlag is used to define a pflag.FlagSet.DurationSliceP flag.
+// In addition to the units supported by time.ParseDuration, durations can be specified in days ("d") and weeks
+// ("w"), e.g. "1d12h".
 type DurationSliceFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    []time.Duration
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []time.Duration
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
 func (f *DurationSliceFlag) Apply(fs *pflag.FlagSet) {
 	fs.DurationSliceVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyDurationUnits(fs, f.GetName())
 }
 
 // GetName implements Flag.
@@ -175,25 +556,85 @@ func (f *DurationSliceFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *DurationSliceFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *DurationSliceFlag) IsRequired() bool {
 	return f.Required
 }
 
+// GetRequiredIf implements ConditionalFlag.
+func (f *DurationSliceFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *DurationSliceFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *DurationSliceFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *DurationSliceFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *DurationSliceFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *DurationSliceFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetDelimiter implements SliceFlag.
+func (f *DurationSliceFlag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *DurationSliceFlag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+
 var _ Flag = &IntFlag{}
+var _ GatedFlag = &IntFlag{}
+var _ AnnotatedFlag = &IntFlag{}
+var _ PlaceholderFlag = &IntFlag{}
+var _ DefaultTextFlag = &IntFlag{}
+var _ ConditionalFlag = &IntFlag{}
+var _ ResolverFlag = &IntFlag{}
 
 // IntFlag is used to define a pflag.FlagSet.IntP flag.
 type IntFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    int
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 int
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
 func (f *IntFlag) Apply(fs *pflag.FlagSet) {
 	fs.IntVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
 }
 
 // GetName implements Flag.
@@ -218,20 +659,266 @@ func (f *IntFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *IntFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *IntFlag) IsRequired() bool {
 	return f.Required
 }
 
-var _ Flag = &IntSliceFlag{}
+// GetRequiredIf implements ConditionalFlag.
+func (f *IntFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *IntFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *IntFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *IntFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *IntFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *IntFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+var _ Flag = &Int64Flag{}
+var _ GatedFlag = &Int64Flag{}
+var _ AnnotatedFlag = &Int64Flag{}
+var _ PlaceholderFlag = &Int64Flag{}
+var _ DefaultTextFlag = &Int64Flag{}
+var _ ConditionalFlag = &Int64Flag{}
+var _ ResolverFlag = &Int64Flag{}
+
+// Int64Flag is used to define a pflag.FlagSet.Int64P flag.
+type Int64Flag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 int64
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *Int64Flag) Apply(fs *pflag.FlagSet) {
+	fs.Int64VarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+}
+
+// GetName implements Flag.
+func (f *Int64Flag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *Int64Flag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *Int64Flag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *Int64Flag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *Int64Flag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *Int64Flag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *Int64Flag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *Int64Flag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *Int64Flag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *Int64Flag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *Int64Flag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *Int64Flag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+var _ SliceFlag = &Int64SliceFlag{}
+var _ GatedFlag = &Int64SliceFlag{}
+var _ AnnotatedFlag = &Int64SliceFlag{}
+var _ PlaceholderFlag = &Int64SliceFlag{}
+var _ DefaultTextFlag = &Int64SliceFlag{}
+var _ ConditionalFlag = &Int64SliceFlag{}
+var _ ResolverFlag = &Int64SliceFlag{}
+
+// Int64SliceFlag is used to define a pflag.FlagSet.Int64SliceP flag.
+type Int64SliceFlag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []int64
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *Int64SliceFlag) Apply(fs *pflag.FlagSet) {
+	fs.Int64SliceVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+}
+
+// GetName implements Flag.
+func (f *Int64SliceFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *Int64SliceFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *Int64SliceFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *Int64SliceFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *Int64SliceFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *Int64SliceFlag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *Int64SliceFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *Int64SliceFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *Int64SliceFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *Int64SliceFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *Int64SliceFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *Int64SliceFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetDelimiter implements SliceFlag.
+func (f *Int64SliceFlag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *Int64SliceFlag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+
+var _ SliceFlag = &IntSliceFlag{}
+var _ GatedFlag = &IntSliceFlag{}
+var _ AnnotatedFlag = &IntSliceFlag{}
+var _ PlaceholderFlag = &IntSliceFlag{}
+var _ DefaultTextFlag = &IntSliceFlag{}
+var _ ConditionalFlag = &IntSliceFlag{}
+var _ ResolverFlag = &IntSliceFlag{}
 
 // IntSliceFlag is used to define a pflag.FlagSet.IntSliceP flag.
 type IntSliceFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    []int
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []int
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
 }
 
 // Apply implements Flag.
@@ -261,25 +948,87 @@ func (f *IntSliceFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *IntSliceFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *IntSliceFlag) IsRequired() bool {
 	return f.Required
 }
 
+// GetRequiredIf implements ConditionalFlag.
+func (f *IntSliceFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *IntSliceFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *IntSliceFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *IntSliceFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *IntSliceFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *IntSliceFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetDelimiter implements SliceFlag.
+func (f *IntSliceFlag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *IntSliceFlag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+
 var _ Flag = &StringFlag{}
+var _ SecretFlag = &StringFlag{}
+var _ GatedFlag = &StringFlag{}
+var _ AnnotatedFlag = &StringFlag{}
+var _ PlaceholderFlag = &StringFlag{}
+var _ DefaultTextFlag = &StringFlag{}
+var _ ConditionalFlag = &StringFlag{}
+var _ ResolverFlag = &StringFlag{}
 
 // StringFlag is used to define a pflag.FlagSet.StringP flag.
 type StringFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    string
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 string
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+	Secret                bool
 }
 
 // Apply implements Flag.
 func (f *StringFlag) Apply(fs *pflag.FlagSet) {
 	fs.StringVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
 }
 
 // GetName implements Flag.
@@ -304,20 +1053,77 @@ func (f *StringFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *StringFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *StringFlag) IsRequired() bool {
 	return f.Required
 }
 
-var _ Flag = &StringSliceFlag{}
+// GetRequiredIf implements ConditionalFlag.
+func (f *StringFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *StringFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *StringFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *StringFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *StringFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *StringFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// IsSecret implements SecretFlag.
+func (f *StringFlag) IsSecret() bool {
+	return f.Secret
+}
+
+var _ SliceFlag = &StringSliceFlag{}
+var _ SecretFlag = &StringSliceFlag{}
+var _ GatedFlag = &StringSliceFlag{}
+var _ AnnotatedFlag = &StringSliceFlag{}
+var _ PlaceholderFlag = &StringSliceFlag{}
+var _ DefaultTextFlag = &StringSliceFlag{}
+var _ ConditionalFlag = &StringSliceFlag{}
+var _ ResolverFlag = &StringSliceFlag{}
 
 // StringSliceFlag is used to define a pflag.FlagSet.StringSliceP flag.
 type StringSliceFlag struct {
-	Name     string
-	Usage    string
-	EnvVar   []string
-	Value    []string
-	Required bool
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 []string
+	Required              bool
+	RequiredIf            string
+	Delimiter             string
+	AppendResolved        bool
+	FeatureGate           string
+	Annotations           map[string]string
+	Secret                bool
 }
 
 // Apply implements Flag.
@@ -347,7 +1153,239 @@ func (f *StringSliceFlag) GetEnvVar() []string {
 	return f.EnvVar
 }
 
+// GetDisableResolvers implements ResolverFlag.
+func (f *StringSliceFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
 // IsRequired implements Flag.
 func (f *StringSliceFlag) IsRequired() bool {
 	return f.Required
 }
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *StringSliceFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *StringSliceFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *StringSliceFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *StringSliceFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *StringSliceFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *StringSliceFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetDelimiter implements SliceFlag.
+func (f *StringSliceFlag) GetDelimiter() string {
+	return f.Delimiter
+}
+
+// IsAppendResolved implements SliceFlag.
+func (f *StringSliceFlag) IsAppendResolved() bool {
+	return f.AppendResolved
+}
+
+// IsSecret implements SecretFlag.
+func (f *StringSliceFlag) IsSecret() bool {
+	return f.Secret
+}
+
+var _ Flag = &StringToIntFlag{}
+var _ GatedFlag = &StringToIntFlag{}
+var _ AnnotatedFlag = &StringToIntFlag{}
+var _ PlaceholderFlag = &StringToIntFlag{}
+var _ DefaultTextFlag = &StringToIntFlag{}
+var _ ConditionalFlag = &StringToIntFlag{}
+var _ ResolverFlag = &StringToIntFlag{}
+
+// StringToIntFlag is used to define a pflag.FlagSet.StringToIntP flag.
+type StringToIntFlag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 map[string]int
+	Required              bool
+	RequiredIf            string
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *StringToIntFlag) Apply(fs *pflag.FlagSet) {
+	fs.StringToIntVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+}
+
+// GetName implements Flag.
+func (f *StringToIntFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *StringToIntFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *StringToIntFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *StringToIntFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *StringToIntFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *StringToIntFlag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *StringToIntFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *StringToIntFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *StringToIntFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *StringToIntFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *StringToIntFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *StringToIntFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+var _ Flag = &StringToInt64Flag{}
+var _ GatedFlag = &StringToInt64Flag{}
+var _ AnnotatedFlag = &StringToInt64Flag{}
+var _ PlaceholderFlag = &StringToInt64Flag{}
+var _ DefaultTextFlag = &StringToInt64Flag{}
+var _ ConditionalFlag = &StringToInt64Flag{}
+var _ ResolverFlag = &StringToInt64Flag{}
+
+// StringToInt64Flag is used to define a pflag.FlagSet.StringToInt64P flag.
+type StringToInt64Flag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 map[string]int64
+	Required              bool
+	RequiredIf            string
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *StringToInt64Flag) Apply(fs *pflag.FlagSet) {
+	fs.StringToInt64VarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+}
+
+// GetName implements Flag.
+func (f *StringToInt64Flag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *StringToInt64Flag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *StringToInt64Flag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *StringToInt64Flag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *StringToInt64Flag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *StringToInt64Flag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *StringToInt64Flag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *StringToInt64Flag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *StringToInt64Flag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *StringToInt64Flag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *StringToInt64Flag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *StringToInt64Flag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
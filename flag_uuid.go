@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var _ GatedFlag = &UUIDFlag{}
+var _ AnnotatedFlag = &UUIDFlag{}
+var _ PlaceholderFlag = &UUIDFlag{}
+var _ DefaultTextFlag = &UUIDFlag{}
+var _ ConditionalFlag = &UUIDFlag{}
+var _ ResolverFlag = &UUIDFlag{}
+
+// UUIDFlag is used to define a flag which only accepts UUIDs in the RFC 4122 format (e.g.
+// 123e4567-e89b-12d3-a456-426614174000). Values are validated during parsing and stored in canonical (lowercase) form,
+// use Context.GetUUID to get the value. The type is listed as "uuid" in the usage.
+type UUIDFlag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 string
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *UUIDFlag) Apply(fs *pflag.FlagSet) {
+	fs.VarP((*uuidValue)(&f.Value), f.GetName(), f.GetShorthand(), usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+}
+
+// GetName implements Flag.
+func (f *UUIDFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *UUIDFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *UUIDFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *UUIDFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *UUIDFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *UUIDFlag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *UUIDFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *UUIDFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *UUIDFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *UUIDFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *UUIDFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *UUIDFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// GetUUID returns the value of a UUIDFlag.
+func (c *Context) GetUUID(name string) (string, error) {
+	f := c.Lookup(name)
+	if f == nil {
+		return "", fmt.Errorf("flag accessed but not defined: %s", name)
+	}
+	if f.Value.Type() != uuidType {
+		return "", fmt.Errorf("trying to get %s value of flag of type %s", uuidType, f.Value.Type())
+	}
+	return f.Value.String(), nil
+}
+
+const uuidType = "uuid"
+
+// uuidValue implements pflag.Value for UUIDFlag.
+type uuidValue string
+
+// Set implements pflag.Value.
+func (v *uuidValue) Set(s string) error {
+	u, err := parseUUID(s)
+	if err != nil {
+		return err
+	}
+	*v = uuidValue(u)
+	return nil
+}
+
+// String implements pflag.Value.
+func (v *uuidValue) String() string {
+	return string(*v)
+}
+
+// Type implements pflag.Value.
+func (v *uuidValue) Type() string {
+	return uuidType
+}
+
+// parseUUID validates that s is a UUID in the RFC 4122 string format (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx) and returns
+// it in lowercase.
+func parseUUID(s string) (string, error) {
+	if len(s) != 36 {
+		return "", errors.New("invalid UUID: expected 36 characters")
+	}
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return "", fmt.Errorf("invalid UUID: expected '-' at position %d", i+1)
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return "", fmt.Errorf("invalid UUID: invalid character %q", r)
+			}
+		}
+	}
+	return strings.ToLower(s), nil
+}
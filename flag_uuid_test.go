@@ -0,0 +1,81 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestUUIDFlag(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			description: "default",
+			expected:    "00000000-0000-0000-0000-000000000000",
+		},
+		{
+			description: "canonical form",
+			args:        []string{"--id", "123E4567-E89B-12D3-A456-426614174000"},
+			expected:    "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			description: "invalid length",
+			args:        []string{"--id", "123e4567"},
+			expectedErr: `invalid UUID: expected 36 characters`,
+		},
+		{
+			description: "missing hyphen",
+			args:        []string{"--id", "123e4567xe89b-12d3-a456-426614174000"},
+			expectedErr: `invalid UUID: expected '-' at position 9`,
+		},
+		{
+			description: "invalid character",
+			args:        []string{"--id", "123e4567-e89b-12d3-a456-42661417400g"},
+			expectedErr: `invalid UUID: invalid character 'g'`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := cli.Command{
+				Usage: "get [flags]",
+				Flags: []cli.Flag{
+					&cli.UUIDFlag{Name: "id", Usage: "ID of the resource", Value: "00000000-0000-0000-0000-000000000000"},
+				},
+				Exec: func(c *cli.Context) error {
+					id, err := c.GetUUID("id")
+					eq(t, nil, err)
+					eq(t, tc.expected, id)
+					return nil
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr == "" {
+				eq(t, nil, err)
+				return
+			}
+			var invalid *cli.ErrInvalidFlagValue
+			eq(t, true, errors.As(err, &invalid))
+			eq(t, true, strings.HasSuffix(invalid.Error(), tc.expectedErr))
+		})
+	}
+
+	t.Run("usage", func(t *testing.T) {
+		var b bytes.Buffer
+		c := cli.Command{
+			Usage: "get [flags]",
+			Flags: []cli.Flag{&cli.UUIDFlag{Name: "id", Usage: "ID of the resource"}},
+			Opts:  cli.Options{ErrWriter: &b},
+			Exec:  func(c *cli.Context) error { return nil },
+		}
+		eq(t, nil, c.Execute([]string{"--help"}))
+		eq(t, true, strings.Contains(b.String(), "--id uuid"))
+	})
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+var _ GatedFlag = &VarFlag{}
+var _ AnnotatedFlag = &VarFlag{}
+var _ PlaceholderFlag = &VarFlag{}
+var _ DefaultTextFlag = &VarFlag{}
+var _ ConditionalFlag = &VarFlag{}
+var _ ResolverFlag = &VarFlag{}
+
+// VarFlag is used to define a flag with a custom type, e.g. a log level or a semantic version. The Value must be a
+// pointer that implements either pflag.Value or encoding.TextUnmarshaler (in which case encoding.TextMarshaler or
+// fmt.Stringer is used to display the default value, if implemented).
+//
+// Each invocation of a command parses into a (shallow) copy of the Value, so that the default is never modified. Use
+// Context.GetValue to get the parsed value, which has the same type as the Value.
+type VarFlag struct {
+	Name                  string
+	Usage                 string
+	Placeholder           string
+	DefaultText           string
+	DisableDefaultInUsage bool
+	EnvVar                []string
+	DisableResolvers      []string
+	Value                 interface{}
+	Required              bool
+	RequiredIf            string
+	Repeated              RepeatPolicy
+	FeatureGate           string
+	Annotations           map[string]string
+}
+
+// Apply implements Flag.
+func (f *VarFlag) Apply(fs *pflag.FlagSet) {
+	fs.VarP(&varValue{value: copyValue(f.Value)}, f.GetName(), f.GetShorthand(), usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+	applyRepeatPolicy(fs, f.GetName(), f.Repeated)
+}
+
+// GetName implements Flag.
+func (f *VarFlag) GetName() string {
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *VarFlag) GetShorthand() string {
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *VarFlag) GetUsage() string {
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *VarFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// GetDisableResolvers implements ResolverFlag.
+func (f *VarFlag) GetDisableResolvers() []string {
+	return f.DisableResolvers
+}
+
+// IsRequired implements Flag.
+func (f *VarFlag) IsRequired() bool {
+	return f.Required
+}
+
+// GetRequiredIf implements ConditionalFlag.
+func (f *VarFlag) GetRequiredIf() string {
+	return f.RequiredIf
+}
+
+// GetFeatureGate implements GatedFlag.
+func (f *VarFlag) GetFeatureGate() string {
+	return f.FeatureGate
+}
+
+// GetAnnotations implements AnnotatedFlag.
+func (f *VarFlag) GetAnnotations() map[string]string {
+	return f.Annotations
+}
+
+// GetPlaceholder implements PlaceholderFlag.
+func (f *VarFlag) GetPlaceholder() string {
+	return f.Placeholder
+}
+
+// GetDefaultText implements DefaultTextFlag.
+func (f *VarFlag) GetDefaultText() string {
+	return f.DefaultText
+}
+
+// IsDefaultInUsageDisabled implements DefaultTextFlag.
+func (f *VarFlag) IsDefaultInUsageDisabled() bool {
+	return f.DisableDefaultInUsage
+}
+
+// validate returns an error if the Value of the flag cannot be used to parse values.
+func (f *VarFlag) validate() error {
+	switch f.Value.(type) {
+	case pflag.Value, encoding.TextUnmarshaler:
+	default:
+		return fmt.Errorf("value of flag %q (%T) must implement pflag.Value or encoding.TextUnmarshaler", f.GetName(), f.Value)
+	}
+	if v := reflect.ValueOf(f.Value); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("value of flag %q (%T) must be a non-nil pointer", f.GetName(), f.Value)
+	}
+	return nil
+}
+
+// GetValue returns the parsed value of a VarFlag.
+func (c *Context) GetValue(name string) (interface{}, error) {
+	f := c.Lookup(name)
+	if f == nil {
+		return nil, fmt.Errorf("flag accessed but not defined: %s", name)
+	}
+	v := f.Value
+	if r, ok := v.(*repeatValue); ok {
+		v = r.Value
+	}
+	vv, ok := v.(*varValue)
+	if !ok {
+		return nil, fmt.Errorf("trying to get value of flag of type %s (not defined by a VarFlag)", f.Value.Type())
+	}
+	return vv.value, nil
+}
+
+// copyValue returns a pointer to a shallow copy of the value that v points to.
+func copyValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return v
+	}
+	c := reflect.New(rv.Elem().Type())
+	c.Elem().Set(rv.Elem())
+	return c.Interface()
+}
+
+// varValue implements pflag.Value for VarFlag.
+type varValue struct {
+	value interface{}
+}
+
+// Set implements pflag.Value.
+func (v *varValue) Set(s string) error {
+	switch value := v.value.(type) {
+	case pflag.Value:
+		return value.Set(s)
+	case encoding.TextUnmarshaler:
+		return value.UnmarshalText([]byte(s))
+	default:
+		return fmt.Errorf("unsupported value type %T", v.value)
+	}
+}
+
+// String implements pflag.Value.
+func (v *varValue) String() string {
+	switch value := v.value.(type) {
+	case pflag.Value:
+		return value.String()
+	case encoding.TextMarshaler:
+		b, _ := value.MarshalText()
+		return string(b)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return ""
+	}
+}
+
+// Type implements pflag.Value. Values that do not implement pflag.Value use the (lowercase) name of their type.
+func (v *varValue) Type() string {
+	if value, ok := v.value.(pflag.Value); ok {
+		return value.Type()
+	}
+	if t := reflect.TypeOf(v.value); t != nil {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Name() != "" {
+			return strings.ToLower(t.Name())
+		}
+	}
+	return "value"
+}
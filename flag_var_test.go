@@ -0,0 +1,87 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+type logLevel int
+
+func (l *logLevel) UnmarshalText(b []byte) error {
+	for i, name := range []string{"debug", "info", "warn"} {
+		if string(b) == name {
+			*l = logLevel(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %q", b)
+}
+
+func (l logLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info", "warn"}[l]), nil
+}
+
+type semver struct{ major, minor int }
+
+func (v *semver) Set(s string) error {
+	_, err := fmt.Sscanf(s, "v%d.%d", &v.major, &v.minor)
+	return err
+}
+
+func (v *semver) String() string { return fmt.Sprintf("v%d.%d", v.major, v.minor) }
+func (v *semver) Type() string   { return "semver" }
+
+func TestVarFlag(t *testing.T) {
+	level, version := logLevel(1), &semver{major: 1}
+
+	var got []string
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			&cli.VarFlag{Name: "log-level", Usage: "Log level", Value: &level},
+			&cli.VarFlag{Name: "version", Usage: "Version to deploy", Value: version},
+		},
+		Opts: cli.Options{ErrWriter: &bytes.Buffer{}},
+		Exec: func(c *cli.Context) error {
+			l, err := c.GetValue("log-level")
+			eq(t, nil, err)
+			v, err := c.GetValue("version")
+			eq(t, nil, err)
+			got = append(got, fmt.Sprintf("%d %s", *l.(*logLevel), v.(*semver)))
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--log-level", "warn", "--version", "v2.3"}))
+	eq(t, nil, c.Execute([]string{}))
+	eq(t, []string{"2 v2.3", "1 v1.0"}, got)
+	eq(t, logLevel(1), level)
+	eq(t, semver{major: 1}, *version)
+
+	t.Run("invalid value", func(t *testing.T) {
+		var invalid *cli.ErrInvalidFlagValue
+		eq(t, true, errors.As(c.Execute([]string{"--log-level", "trace"}), &invalid))
+		eq(t, "log-level", invalid.Name)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		var b bytes.Buffer
+		c.Opts.ErrWriter = &b
+		eq(t, nil, c.Execute([]string{"--help"}))
+		eq(t, true, strings.Contains(b.String(), `--log-level loglevel   Log level (default info)`))
+		eq(t, true, strings.Contains(b.String(), `--version semver       Version to deploy (default v1.0)`))
+	})
+
+	t.Run("unsupported value", func(t *testing.T) {
+		c := cli.Command{
+			Usage: "deploy [flags]",
+			Flags: []cli.Flag{&cli.VarFlag{Name: "level", Value: 1}},
+			Exec:  func(c *cli.Context) error { return nil },
+		}
+		eq(t, `parsing command: misconfigured command "deploy": value of flag "level" (int) must implement pflag.Value or encoding.TextUnmarshaler`, c.Execute([]string{}).Error())
+	})
+}
@@ -0,0 +1,156 @@
+// Package gcpsecrets provides a cli.FlagResolver for GCP Secret Manager. Flags opt in to the resolver by setting the
+// Annotation to the name of a secret:
+//
+//	&cli.StringFlag{
+//		Name:        "token",
+//		Annotations: map[string]string{gcpsecrets.Annotation: "api-token"},
+//	}
+//
+// The secrets are accessed using the Secret Manager REST API, so that the package does not depend on the GCP SDK.
+package gcpsecrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Annotation is the key of the flag annotation that holds the name of the secret for the flag. The name is either
+// the full resource name of a secret version (e.g. "projects/my-project/secrets/api-token/versions/2") or the name
+// of a secret in the project of the Resolver, in which case the latest version is used.
+const Annotation = "gcp-secret"
+
+// DefaultEndpoint is the endpoint of the Secret Manager API.
+const DefaultEndpoint = "https://secretmanager.googleapis.com"
+
+// Resolver implements cli.FlagResolver by accessing secrets in GCP Secret Manager. Flags that cannot be resolved
+// because of an error (e.g. missing permissions) are treated as unresolved, and the last error is available from Err.
+type Resolver struct {
+	// Project that holds the secrets, used for secrets that are not given with their full resource name.
+	Project string
+
+	// Token returns the OAuth2 access token used to authenticate the requests, e.g. using
+	// golang.org/x/oauth2/google.DefaultTokenSource.
+	Token func(ctx context.Context) (string, error)
+
+	// Endpoint (optional) of the API. Defaults to DefaultEndpoint.
+	Endpoint string
+
+	// HTTPClient (optional) used for the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout (optional) for accessing a secret. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	err error
+}
+
+var _ cli.NamedResolver = &Resolver{}
+var _ cli.FlagSource = &Resolver{}
+
+// Resolve implements cli.FlagResolver.
+func (r *Resolver) Resolve(flag cli.Flag) (string, bool) {
+	name, ok := r.secretName(flag)
+	if !ok {
+		return "", false
+	}
+	v, err := r.access(name)
+	if err != nil {
+		r.err = fmt.Errorf("accessing secret %q for flag %q: %w", name, flag.GetName(), err)
+		return "", false
+	}
+	return v, true
+}
+
+// Name implements cli.NamedResolver.
+func (r *Resolver) Name() string {
+	return "gcp-secret-manager"
+}
+
+// Source implements cli.FlagSource.
+func (r *Resolver) Source(flag cli.Flag) string {
+	name, _ := r.secretName(flag)
+	return "gcp-secret " + name
+}
+
+// Err returns the last error that occurred when resolving a flag.
+func (r *Resolver) Err() error {
+	return r.err
+}
+
+// secretName returns the full resource name of the secret version for the flag.
+func (r *Resolver) secretName(flag cli.Flag) (string, bool) {
+	af, ok := flag.(cli.AnnotatedFlag)
+	if !ok {
+		return "", false
+	}
+	name, ok := af.GetAnnotations()[Annotation]
+	if !ok || name == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(name, "projects/") {
+		name = fmt.Sprintf("projects/%s/secrets/%s/versions/latest", r.Project, name)
+	}
+	return name, true
+}
+
+// access returns the value of the secret version.
+func (r *Resolver) access(name string) (string, error) {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	if r.Token != nil {
+		token, err := r.Token(ctx)
+		if err != nil {
+			return "", fmt.Errorf("getting access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding payload: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,74 @@
+package gcpsecrets_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/gcpsecrets"
+)
+
+func TestResolver(t *testing.T) {
+	secrets := map[string]string{
+		"/v1/projects/my-project/secrets/api-token/versions/latest:access": "s3cr3t",
+		"/v1/projects/other/secrets/db-password/versions/2:access":         "hunter2",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthenticated", http.StatusUnauthorized)
+			return
+		}
+		v, ok := secrets[r.URL.Path]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q, "payload": {"data": %q}}`, r.URL.Path, base64.StdEncoding.EncodeToString([]byte(v)))
+	}))
+	defer server.Close()
+
+	resolver := &gcpsecrets.Resolver{
+		Project:  "my-project",
+		Endpoint: server.URL,
+		Token:    func(context.Context) (string, error) { return "token", nil },
+	}
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "token", Annotations: map[string]string{gcpsecrets.Annotation: "api-token"}},
+			&cli.StringFlag{Name: "password", Annotations: map[string]string{gcpsecrets.Annotation: "projects/other/secrets/db-password/versions/2"}},
+			&cli.StringFlag{Name: "missing", Annotations: map[string]string{gcpsecrets.Annotation: "missing"}},
+			&cli.StringFlag{Name: "region", Value: "europe-north1"},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{resolver}},
+		Exec: func(c *cli.Context) error {
+			var got []string
+			for _, name := range []string{"token", "password", "missing", "region"} {
+				v, err := c.GetString(name)
+				if err != nil {
+					return err
+				}
+				got = append(got, v)
+			}
+			if expected := []string{"s3cr3t", "hunter2", "", "europe-north1"}; !reflect.DeepEqual(expected, got) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+			if source := c.FlagSource("token"); source != "gcp-secret projects/my-project/secrets/api-token/versions/latest" {
+				t.Errorf("unexpected source: %s", source)
+			}
+			return nil
+		},
+	}
+	if err := c.Execute(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedErr := `accessing secret "projects/my-project/secrets/missing/versions/latest" for flag "missing": unexpected status 404 Not Found: not found`
+	if err := resolver.Err(); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
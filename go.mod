@@ -1,5 +1,13 @@
 module github.com/itsdalmo/cli
 
-go 1.14
+go 1.21
 
-require github.com/spf13/pflag v1.0.5
+require (
+	github.com/rogpeppe/go-internal v1.12.0
+	github.com/spf13/pflag v1.0.5
+	golang.org/x/sys v0.5.0
+	golang.org/x/term v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/tools v0.1.12 // indirect
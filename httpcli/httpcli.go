@@ -0,0 +1,83 @@
+// Package httpcli exposes a cli.Command tree over HTTP, so that the commands can be invoked by web UIs and bots
+// without shelling out:
+//
+//	POST / {"args": ["deploy", "--dry-run"], "stdin": "..."}
+//
+// returns a Result with the captured output, exit code and result (see cli.Context.SetResult) of the command, and
+// GET / returns the cli.Spec of the command tree (for discovery). Each request executes a clone of the command (see
+// cli.Command.Clone).
+package httpcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/itsdalmo/cli"
+)
+
+// maxRequestSize is the maximum size of a request body.
+const maxRequestSize = 1 << 20
+
+// Request is the body of a POST request.
+type Request struct {
+	Args  []string `json:"args"`
+	Stdin string   `json:"stdin,omitempty"`
+}
+
+// Result is the response to a POST request. Errors returned by the command are written to Stderr.
+type Result struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exitCode"`
+
+	// Result is the value set by the command using cli.Context.SetResult (if any), which must be JSON encodable.
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Handler returns a http.Handler that executes the command.
+func Handler(cmd *cli.Command) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			spec, err := cli.NewSpec(cmd.Clone())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, spec)
+		case http.MethodPost:
+			var req Request
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("decoding request: %s", err), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, Execute(cmd, req))
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// Execute executes a clone of the command with the arguments and stdin of the request, and returns the captured
+// output.
+func Execute(cmd *cli.Command, req Request) Result {
+	var stdout, stderr bytes.Buffer
+	c := cmd.Clone()
+	c.Opts.Reader, c.Opts.Writer, c.Opts.ErrWriter = strings.NewReader(req.Stdin), &stdout, &stderr
+
+	result, err := c.ExecuteResult(req.Args)
+	if err != nil {
+		fmt.Fprintln(&stderr, err)
+	}
+	return Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cli.ExitCode(err), Result: result}
+}
+
+// writeJSON writes v as the JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
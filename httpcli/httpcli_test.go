@@ -0,0 +1,120 @@
+package httpcli_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/httpcli"
+)
+
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Usage: "admin [command]",
+		Subcommands: []*cli.Command{
+			{
+				Usage: "echo [flags] <words>...",
+				Flags: []cli.Flag{&cli.BoolFlag{Name: "stdin"}},
+				Exec: func(c *cli.Context) error {
+					if stdin, _ := c.GetBool("stdin"); stdin {
+						b, err := c.ReadStdin()
+						if err != nil {
+							return err
+						}
+						c.Printf("%s", b)
+						return nil
+					}
+					c.Println(strings.Join(c.Args(), " "))
+					c.SetResult(c.Args())
+					return nil
+				},
+			},
+			{
+				Usage: "fail",
+				Exec: func(c *cli.Context) error {
+					return &cli.ExitError{Code: 2, Err: errors.New("failed")}
+				},
+			},
+		},
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(httpcli.Handler(newCommand()))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		body        string
+		expected    httpcli.Result
+	}{
+		{
+			description: "args",
+			body:        `{"args": ["echo", "hello", "world"]}`,
+			expected: httpcli.Result{
+				Stdout: "hello world\n",
+				Result: []interface{}{"hello", "world"},
+			},
+		},
+		{
+			description: "stdin",
+			body:        `{"args": ["echo", "--stdin"], "stdin": "from stdin"}`,
+			expected:    httpcli.Result{Stdout: "from stdin"},
+		},
+		{
+			description: "exit code",
+			body:        `{"args": ["fail"]}`,
+			expected:    httpcli.Result{Stderr: "failed\n", ExitCode: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			resp, err := http.Post(server.URL, "application/json", strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var result httpcli.Result
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.expected, result) {
+				t.Errorf("expected %+v, got %+v", tc.expected, result)
+			}
+		})
+	}
+
+	t.Run("spec", func(t *testing.T) {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var spec cli.Spec
+		if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.Name != "admin" || len(spec.Subcommands) != 2 {
+			t.Errorf("unexpected spec: %+v", spec)
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		resp, err := http.Post(server.URL, "application/json", strings.NewReader("{"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package cli
+
+// Translator translates the user-facing strings of the framework, such as the section headings in usage texts and
+// the errors returned when parsing a command. Messages are given in English, and some are format strings (e.g.
+// "missing required flags %v") where the translation must contain the same verbs. See Messages for all messages.
+// Errors from pflag (e.g. for invalid flag values) and the operating system are not translated.
+type Translator interface {
+	Translate(msg string) string
+}
+
+// Catalog implements Translator using a map from the English messages to their translations. Messages that are not
+// in the catalog are used as-is.
+type Catalog map[string]string
+
+// Translate implements Translator.
+func (c Catalog) Translate(msg string) string {
+	if t, ok := c[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Messages are the strings of the framework that are passed to the Translator.
+var Messages = []string{
+	"Usage:",
+	"Available Commands:",
+	"Flags:",
+	"%s Flags:",
+	"Global Flags:",
+	"[command]",
+	"[flags]",
+	"Examples:",
+	"parsing command: %w",
+	"%s: no subcommand specified (available: %s). See --help",
+	"missing required flags %v",
+	"invalid argument %q for %q, expected one of: %s",
+	"A new version of %s is available: %s -> %s",
+	"[y/N]",
+	"invalid option %q",
+	"warning: unknown environment variables %v",
+	"warning: %q is deprecated, use %q instead",
+	"warning: flag %q is set from %s, ignoring the different value of %s",
+	"unknown flag: %s",
+	"ambiguous flag: %s (matches %s)",
+	"flag needs an argument: %s",
+	"flag can only be specified once",
+	"feature %q is not enabled",
+	"another instance is running (pid %d)",
+}
+
+// tr translates the message using the Translator of the command (if any).
+func (c *Command) tr(msg string) string {
+	if t := c.options().Translator; t != nil {
+		return t.Translate(msg)
+	}
+	return msg
+}
+
+// translate translates the message using tr, or returns it as-is if tr is nil (e.g. for errors that are not
+// returned by a command).
+func translate(tr func(string) string, msg string) string {
+	if tr == nil {
+		return msg
+	}
+	return tr(msg)
+}
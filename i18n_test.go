@@ -0,0 +1,148 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestTranslator(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "skriver [kommando]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region", Usage: "Region", Required: true}},
+		Opts: cli.Options{
+			ErrWriter: &b,
+			Translator: cli.Catalog{
+				"Usage:":              "Bruk:",
+				"Available Commands:": "Kommandoer:",
+				"Global Flags:":       "Globale flagg:",
+				"[command]":           "[kommando]",
+				"[flags]":             "[flagg]",
+				"parsing command: %w": "ugyldig kommando: %w",
+				"%s: no subcommand specified (available: %s). See --help": "%s: mangler kommando (tilgjengelige: %s). Se --help",
+				"missing required flags %v":                               "mangler påkrevde flagg %v",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{Usage: "skriv", Help: "Skriv ut", Exec: func(c *cli.Context) error { return nil }},
+		},
+	}
+
+	eq(t, nil, c.Execute([]string{"--help"}))
+	expected := `Bruk:
+  skriver [kommando] [flagg]
+
+Kommandoer:
+  skriv        Skriv ut
+
+Flags:
+      --region string   Region
+
+`
+	eq(t, expected, b.String())
+
+	b.Reset()
+	eq(t, nil, c.Execute([]string{"skriv", "--help"}))
+	eq(t, true, bytes.HasPrefix(b.Bytes(), []byte("Skriv ut\n\nBruk:\n  skriver skriv\n\nGlobale flagg:\n")))
+
+	err := c.Execute([]string{})
+	eq(t, "ugyldig kommando: skriver: mangler kommando (tilgjengelige: skriv). Se --help", err.Error())
+
+	err = c.Execute([]string{"skriv"})
+	eq(t, "ugyldig kommando: mangler påkrevde flagg [region]", err.Error())
+}
+
+func TestTranslator_Errors(t *testing.T) {
+	c := cli.Command{
+		Usage: "skriver",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region", Usage: "Region", Repeated: cli.RepeatError},
+			&cli.BoolFlag{Name: "verbose", Usage: "Verbose"},
+			&cli.BoolFlag{Name: "version-check", Usage: "Version check"},
+			&cli.BoolFlag{Name: "canary", Usage: "Canary", FeatureGate: "canary"},
+		},
+		Opts: cli.Options{
+			AllowFlagAbbreviations: true,
+			Translator: cli.Catalog{
+				"parsing command: %w":             "ugyldig kommando: %w",
+				"unknown flag: %s":                "ukjent flagg: %s",
+				"ambiguous flag: %s (matches %s)": "tvetydig flagg: %s (passer %s)",
+				"flag needs an argument: %s":      "flagget mangler verdi: %s",
+				"flag can only be specified once": "flagget kan bare angis én gang",
+				"feature %q is not enabled":       "funksjonen %q er ikke aktivert",
+			},
+		},
+		Exec: func(c *cli.Context) error { return nil },
+	}
+
+	tests := []struct {
+		description string
+		args        []string
+		expected    string
+	}{
+		{
+			description: "unknown flag",
+			args:        []string{"--zone"},
+			expected:    "ugyldig kommando: ukjent flagg: --zone",
+		},
+		{
+			description: "ambiguous flag",
+			args:        []string{"--ver"},
+			expected:    "ugyldig kommando: tvetydig flagg: --ver (passer --verbose, --version-check)",
+		},
+		{
+			description: "missing flag value",
+			args:        []string{"--region"},
+			expected:    "ugyldig kommando: flagget mangler verdi: --region",
+		},
+		{
+			description: "repeated flag",
+			args:        []string{"--region", "eu-west-1", "--region", "us-east-1"},
+			expected:    `ugyldig kommando: invalid argument "us-east-1" for "--region" flag: flagget kan bare angis én gang`,
+		},
+		{
+			description: "feature not enabled",
+			args:        []string{"--canary"},
+			expected:    `ugyldig kommando: funksjonen "canary" er ikke aktivert`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := c.Execute(tc.args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			eq(t, tc.expected, err.Error())
+		})
+	}
+
+	t.Run("already running", func(t *testing.T) {
+		var (
+			path   = filepath.Join(t.TempDir(), "skriver.lock")
+			nested error
+		)
+		c := &cli.Command{
+			Usage:          "skriver",
+			SingleInstance: true,
+			LockFile:       path,
+		}
+		c.Exec = func(*cli.Context) error {
+			nested = (&cli.Command{
+				Usage:          "skriver",
+				SingleInstance: true,
+				LockFile:       path,
+				Opts:           cli.Options{Translator: cli.Catalog{"another instance is running (pid %d)": "kjører allerede (pid %d)"}},
+				Exec:           func(*cli.Context) error { return nil },
+			}).Execute([]string{})
+			return nil
+		}
+		eq(t, nil, c.Execute([]string{}))
+		eq(t, fmt.Sprintf("kjører allerede (pid %d)", os.Getpid()), nested.Error())
+	})
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+// ErrNonInteractive is returned by the prompt helpers when input is required but the command is running with YesFlag
+// set.
+var ErrNonInteractive = errors.New("input required but running non-interactively")
+
+var _ Flag = &YesFlag{}
+
+// YesFlag defines the standard --yes/-y flag, which makes Context.Confirm assume yes and Context.Interactive report
+// false. Set the Name to e.g. "non-interactive" to use a different flag name.
+type YesFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   []string
+	Value    bool
+	Required bool
+}
+
+// Apply implements Flag.
+func (f *YesFlag) Apply(fs *pflag.FlagSet) {
+	fs.BoolVarP(&f.Value, f.GetName(), f.GetShorthand(), f.Value, usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+}
+
+// GetName implements Flag.
+func (f *YesFlag) GetName() string {
+	if f.Name == "" {
+		return "yes"
+	}
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *YesFlag) GetShorthand() string {
+	if f.Name == "" {
+		return "y"
+	}
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *YesFlag) GetUsage() string {
+	if f.Usage == "" {
+		return "Assume yes for all confirmations and never prompt for input"
+	}
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *YesFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// IsRequired implements Flag.
+func (f *YesFlag) IsRequired() bool {
+	return f.Required
+}
+
+// Interactive returns true if the command is allowed to prompt the user, i.e. the configured Reader is a terminal and
+// YesFlag has not been set.
+func (c *Context) Interactive() bool {
+	return c.IsStdinTTY() && !c.assumeYes()
+}
+
+// assumeYes returns true if the YesFlag of the command has been set.
+func (c *Context) assumeYes() bool {
+	f := c.lookupFlag(func(f Flag) bool {
+		_, ok := f.(*YesFlag)
+		return ok
+	})
+	return f != nil && f.Value.String() == "true"
+}
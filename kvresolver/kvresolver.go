@@ -0,0 +1,108 @@
+// Package kvresolver provides a cli.FlagResolver that reads flag values from a key/value store such as Consul or
+// etcd, which allows the defaults of CLI tools operated by a fleet to be managed centrally. The keys are made from a
+// prefix, the path of the command that is executed and the name of the flag, and the resolver falls back to the keys
+// of the parent commands. E.g. for the --region flag of "app deploy prod":
+//
+//	app/deploy/prod/region
+//	app/deploy/region
+//	app/region
+//
+// The stores are accessed using their HTTP APIs, so that the package does not depend on their client libraries.
+package kvresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Store is a key/value store.
+type Store interface {
+	// Get returns the value of the key, and false if the key does not exist.
+	Get(ctx context.Context, key string) (string, bool, error)
+}
+
+// Resolver implements cli.PathResolver by reading flag values from a Store. Flags that cannot be resolved because of
+// an error (e.g. the store being unavailable) are treated as unresolved, and the last error is available from Err.
+type Resolver struct {
+	// Store to read the values from, e.g. Consul or Etcd.
+	Store Store
+
+	// Prefix (optional) of the keys. Defaults to the name of the root command.
+	Prefix string
+
+	// Timeout (optional) for resolving a flag. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	keys map[string]string
+	err  error
+}
+
+var _ cli.PathResolver = &Resolver{}
+var _ cli.NamedResolver = &Resolver{}
+var _ cli.FlagSource = &Resolver{}
+
+// Resolve implements cli.FlagResolver, using only the Prefix (since the command is not known).
+func (r *Resolver) Resolve(flag cli.Flag) (string, bool) {
+	return r.ResolvePath(nil, flag)
+}
+
+// ResolvePath implements cli.PathResolver.
+func (r *Resolver) ResolvePath(path []string, flag cli.Flag) (string, bool) {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, key := range r.candidates(path, flag.GetName()) {
+		v, found, err := r.Store.Get(ctx, key)
+		if err != nil {
+			r.err = fmt.Errorf("reading key %q for flag %q: %w", key, flag.GetName(), err)
+			return "", false
+		}
+		if found {
+			if r.keys == nil {
+				r.keys = make(map[string]string)
+			}
+			r.keys[flag.GetName()] = key
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Name implements cli.NamedResolver.
+func (r *Resolver) Name() string {
+	return "kv"
+}
+
+// Source implements cli.FlagSource.
+func (r *Resolver) Source(flag cli.Flag) string {
+	return "kv " + r.keys[flag.GetName()]
+}
+
+// Err returns the last error that occurred when resolving a flag.
+func (r *Resolver) Err() error {
+	return r.err
+}
+
+// candidates returns the keys for the flag in order of precedence.
+func (r *Resolver) candidates(path []string, name string) []string {
+	prefix := r.Prefix
+	if prefix == "" && len(path) > 0 {
+		prefix = path[0]
+	}
+	if len(path) > 0 {
+		path = path[1:]
+	}
+	var keys []string
+	for i := len(path); i >= 0; i-- {
+		keys = append(keys, strings.Join(append(append([]string{prefix}, path[:i]...), name), "/"))
+	}
+	return keys
+}
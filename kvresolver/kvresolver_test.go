@@ -0,0 +1,80 @@
+package kvresolver_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/kvresolver"
+)
+
+type store map[string]string
+
+func (s store) Get(_ context.Context, key string) (string, bool, error) {
+	if key == "app/broken" {
+		return "", false, errors.New("unavailable")
+	}
+	v, ok := s[key]
+	return v, ok, nil
+}
+
+func TestResolver(t *testing.T) {
+	resolver := &kvresolver.Resolver{Store: store{
+		"app/region":             "eu-west-1",
+		"app/deploy/region":      "eu-north-1",
+		"app/deploy/prod/region": "us-east-1",
+		"app/deploy/replicas":    "3",
+	}}
+
+	var got []string
+	exec := func(c *cli.Context) error {
+		region, _ := c.GetString("region")
+		replicas, _ := c.GetInt("replicas")
+		got = append(got, region, c.FlagSource("region"), c.FlagSource("replicas"))
+		if replicas != 0 && replicas != 3 {
+			t.Errorf("unexpected replicas: %d", replicas)
+		}
+		return nil
+	}
+	c := cli.Command{
+		Usage: "app [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "region"}},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "deploy [command]",
+				Flags: []cli.Flag{&cli.IntFlag{Name: "replicas"}},
+				Subcommands: []*cli.Command{
+					{Usage: "prod", Exec: exec},
+					{Usage: "staging", Exec: exec},
+				},
+			},
+			{Usage: "status", Exec: exec},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{resolver}},
+	}
+	for _, args := range [][]string{{"deploy", "prod"}, {"deploy", "staging"}, {"status"}} {
+		if err := c.Execute(args); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	expected := []string{
+		"us-east-1", "kv app/deploy/prod/region", "kv app/deploy/replicas",
+		"eu-north-1", "kv app/deploy/region", "kv app/deploy/replicas",
+		"eu-west-1", "kv app/region", "default",
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		resolver := &kvresolver.Resolver{Store: store{}, Prefix: "app"}
+		if _, found := resolver.Resolve(&cli.StringFlag{Name: "broken"}); found {
+			t.Error("expected flag to be unresolved")
+		}
+		if err := resolver.Err(); err == nil || err.Error() != `reading key "app/broken" for flag "broken": unavailable` {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
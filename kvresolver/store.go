@@ -0,0 +1,130 @@
+package kvresolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Consul implements Store using the KV API of Consul.
+type Consul struct {
+	// Address of the Consul agent. Defaults to "http://127.0.0.1:8500".
+	Address string
+
+	// Token (optional) is the ACL token used for the requests.
+	Token string
+
+	// HTTPClient (optional) used for the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Get implements Store.
+func (s *Consul) Get(ctx context.Context, key string) (string, bool, error) {
+	address := s.Address
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(address, "/")+"/v1/kv/"+escapeKey(key)+"?raw", nil)
+	if err != nil {
+		return "", false, err
+	}
+	if s.Token != "" {
+		req.Header.Set("X-Consul-Token", s.Token)
+	}
+	b, status, err := do(s.HTTPClient, req)
+	if err != nil || status == http.StatusNotFound {
+		return "", false, err
+	}
+	return string(b), true, nil
+}
+
+// Etcd implements Store using the JSON gateway of the etcd v3 API.
+type Etcd struct {
+	// Endpoint of the etcd server. Defaults to "http://127.0.0.1:2379".
+	Endpoint string
+
+	// Token (optional) is the authentication token used for the requests.
+	Token string
+
+	// HTTPClient (optional) used for the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Get implements Store.
+func (s *Etcd) Get(ctx context.Context, key string) (string, bool, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "http://127.0.0.1:2379"
+	}
+	body, err := json.Marshal(map[string]string{"key": base64.StdEncoding.EncodeToString([]byte(key))})
+	if err != nil {
+		return "", false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v3/kv/range", bytes.NewReader(body))
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", s.Token)
+	}
+	b, status, err := do(s.HTTPClient, req)
+	if err != nil {
+		return "", false, err
+	}
+	if status == http.StatusNotFound {
+		return "", false, fmt.Errorf("unexpected status %d", status)
+	}
+
+	var resp struct {
+		KVs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return "", false, fmt.Errorf("decoding response: %w", err)
+	}
+	if len(resp.KVs) == 0 {
+		return "", false, nil
+	}
+	v, err := base64.StdEncoding.DecodeString(resp.KVs[0].Value)
+	if err != nil {
+		return "", false, fmt.Errorf("decoding value: %w", err)
+	}
+	return string(v), true, nil
+}
+
+// escapeKey escapes each segment of the key for use in a URL path.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// do sends the request and returns the body of the response, which is an error unless the status is OK or not found.
+func do(client *http.Client, req *http.Request) ([]byte, int, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return nil, 0, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	return b, resp.StatusCode, nil
+}
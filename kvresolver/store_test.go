@@ -0,0 +1,70 @@
+package kvresolver_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/itsdalmo/cli/kvresolver"
+)
+
+func TestStores(t *testing.T) {
+	values := map[string]string{"app/deploy/region": "eu-north-1"}
+
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "token" {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return
+		}
+		v, ok := values[r.URL.Path[len("/v1/kv/"):]]
+		if !ok || !r.URL.Query().Has("raw") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, v)
+	}))
+	defer consul.Close()
+
+	etcd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Key []byte }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/v3/kv/range" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		v, ok := values[string(req.Key)]
+		if !ok {
+			fmt.Fprint(w, `{"header": {}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"kvs": [{"key": %q, "value": %q}], "count": "1"}`, req.Key, base64.StdEncoding.EncodeToString([]byte(v)))
+	}))
+	defer etcd.Close()
+
+	stores := map[string]kvresolver.Store{
+		"consul": &kvresolver.Consul{Address: consul.URL, Token: "token"},
+		"etcd":   &kvresolver.Etcd{Endpoint: etcd.URL},
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			v, found, err := store.Get(context.Background(), "app/deploy/region")
+			if err != nil || !found || v != "eu-north-1" {
+				t.Errorf("expected value to be found, got %q, %t, %v", v, found, err)
+			}
+			v, found, err = store.Get(context.Background(), "app/region")
+			if err != nil || found || v != "" {
+				t.Errorf("expected value to be missing, got %q, %t, %v", v, found, err)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		_, _, err := (&kvresolver.Consul{Address: consul.URL}).Get(context.Background(), "app/region")
+		if err == nil || err.Error() != "unexpected status 403 Forbidden: ACL not found" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrAlreadyRunning is returned when executing a command with SingleInstance while another instance of the command
+// is running.
+type ErrAlreadyRunning struct {
+	PID int // Zero if the pid of the other instance could not be read.
+
+	tr func(string) string
+}
+
+// Error implements errors.Error.
+func (e *ErrAlreadyRunning) Error() string {
+	return fmt.Sprintf(translate(e.tr, "another instance is running (pid %d)"), e.PID)
+}
+
+// lock acquires the lock file for the command (if it is a SingleInstance command), and returns a function that
+// releases the lock. The lock is held using flock (or LockFileEx on Windows), so a lock file that was left behind by a
+// process that is no longer running is not locked.
+func (c *Command) lock() (func(), error) {
+	if !c.SingleInstance {
+		return func() {}, nil
+	}
+	path := c.LockFile
+	if path == "" {
+		path = filepath.Join(os.TempDir(), strings.ReplaceAll(c.path(), " ", "-")+".lock")
+	}
+	unlock, err := lockFile(path)
+	var running *ErrAlreadyRunning
+	if errors.As(err, &running) {
+		running.tr = c.tr
+	}
+	return unlock, err
+}
+
+// fileLock is the platform specific locking of an open lock file.
+type fileLock struct {
+	// tryLock returns false if the file is locked by another process (or open file).
+	tryLock func(f *os.File) (bool, error)
+
+	// release removes the lock file and releases the lock.
+	release func(f *os.File, path string)
+}
+
+// acquire opens (or creates) the lock file and locks it, after which the pid of the process is written to it.
+func (l fileLock) acquire(path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening lock file: %w", err)
+		}
+		locked, err := l.tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("locking lock file: %w", err)
+		}
+		if !locked {
+			f.Close()
+			return nil, &ErrAlreadyRunning{PID: readLockPID(path)}
+		}
+
+		// The previous holder removes the file before releasing the lock, in which case the lock is for a file
+		// that no longer exists (or has been replaced), and must be acquired again.
+		if !sameFile(f, path) {
+			l.release(f, "")
+			continue
+		}
+		if err := writeLockPID(f); err != nil {
+			l.release(f, path)
+			return nil, fmt.Errorf("writing lock file: %w", err)
+		}
+		return func() { l.release(f, path) }, nil
+	}
+}
+
+// sameFile returns true if the open file is the file at path.
+func sameFile(f *os.File, path string) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	return err == nil && os.SameFile(fi, current)
+}
+
+// writeLockPID replaces the contents of the lock file with the pid of the process.
+func writeLockPID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	return err
+}
+
+// readLockPID returns the pid in the lock file, or 0 if it cannot be read (e.g. because it has not been written yet).
+func readLockPID(path string) int {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+	return pid
+}
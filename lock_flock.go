@@ -0,0 +1,31 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile acquires the lock file using flock.
+func lockFile(path string) (func(), error) {
+	return fileLock{
+		tryLock: func(f *os.File) (bool, error) {
+			err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				return false, nil
+			}
+			return err == nil, err
+		},
+		release: func(f *os.File, path string) {
+			// The file is removed while it is locked, so that it cannot be locked by another process after being
+			// released and then removed.
+			if path != "" {
+				os.Remove(path)
+			}
+			syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+			f.Close()
+		},
+	}.acquire(path)
+}
@@ -0,0 +1,87 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestSingleInstance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.lock")
+
+	var nested error
+	c := &cli.Command{
+		Usage:          "deploy",
+		SingleInstance: true,
+		LockFile:       path,
+	}
+	c.Exec = func(ctx *cli.Context) error {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected lock file: %s", err)
+		}
+		nested = (&cli.Command{
+			Usage:          "deploy",
+			SingleInstance: true,
+			LockFile:       path,
+			Exec:           func(c *cli.Context) error { return nil },
+		}).Execute([]string{})
+		return nil
+	}
+	eq(t, nil, c.Execute([]string{}))
+
+	var running *cli.ErrAlreadyRunning
+	eq(t, true, errors.As(nested, &running))
+	eq(t, fmt.Sprintf("another instance is running (pid %d)", os.Getpid()), nested.Error())
+
+	_, err := os.Stat(path)
+	eq(t, true, errors.Is(err, os.ErrNotExist))
+
+	t.Run("stale lock", func(t *testing.T) {
+		if err := ioutil.WriteFile(path, []byte("not-a-pid"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c.Exec = func(ctx *cli.Context) error { return nil }
+		eq(t, nil, c.Execute([]string{}))
+	})
+}
+
+func TestSingleInstance_Concurrent(t *testing.T) {
+	var (
+		path    = filepath.Join(t.TempDir(), "deploy.lock")
+		running int32
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := (&cli.Command{
+				Usage:          "deploy",
+				SingleInstance: true,
+				LockFile:       path,
+				Exec: func(c *cli.Context) error {
+					if n := atomic.AddInt32(&running, 1); n > 1 {
+						t.Errorf("%d instances running", n)
+					}
+					time.Sleep(time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					return nil
+				},
+			}).Execute([]string{})
+
+			var running *cli.ErrAlreadyRunning
+			if err != nil && !errors.As(err, &running) {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
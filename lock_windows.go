@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile acquires the lock file using LockFileEx. The locked byte is beyond the pid (at offset 2^32), so that the pid
+// can still be read by other processes.
+func lockFile(path string) (func(), error) {
+	return fileLock{
+		tryLock: func(f *os.File) (bool, error) {
+			flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+			err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, &windows.Overlapped{OffsetHigh: 1})
+			if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+				return false, nil
+			}
+			return err == nil, err
+		},
+		release: func(f *os.File, path string) {
+			windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &windows.Overlapped{OffsetHigh: 1})
+			f.Close()
+			// Files that are open cannot be removed on Windows, so the file is only removed if it is not about to be
+			// locked by another process.
+			if path != "" {
+				os.Remove(path)
+			}
+		},
+	}.acquire(path)
+}
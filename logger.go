@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"log/slog"
+)
+
+// Names of the flags added to the root command when Options.VerbosityFlags is set.
+const (
+	verboseFlagName = "verbose"
+	quietFlagName   = "quiet"
+)
+
+// verbosityFlags returns the --verbose and --quiet flags.
+func verbosityFlags() []Flag {
+	return []Flag{
+		&CountFlag{
+			Name:  verboseFlagName + ", v",
+			Usage: "Increase the verbosity of diagnostic output (can be repeated)",
+		},
+		&BoolFlag{
+			Name:  quietFlagName + ", q",
+			Usage: "Only print errors in diagnostic output",
+		},
+	}
+}
+
+// Logger returns a logger writing to the configured ErrWriter. By default only warnings and errors are logged, when
+// Options.VerbosityFlags is set the level can be lowered to info (-v) and debug (-vv), or raised to error (--quiet).
+func (c *Context) Logger() *slog.Logger {
+	if c.logger == nil {
+		c.logger = slog.New(slog.NewTextHandler(c.cmd.options().ErrWriter, &slog.HandlerOptions{Level: c.logLevel()}))
+	}
+	return c.logger
+}
+
+// logLevel derives the log level from the verbosity flags, which are only used when Options.VerbosityFlags is set (so
+// that flags with the same names defined by the application do not change the level).
+func (c *Context) logLevel() slog.Level {
+	if !c.cmd.options().VerbosityFlags {
+		return slog.LevelWarn
+	}
+	if quiet, err := c.GetBool(quietFlagName); err == nil && quiet {
+		return slog.LevelError
+	}
+	verbose, _ := c.GetCount(verboseFlagName)
+	switch {
+	case verbose >= 2:
+		return slog.LevelDebug
+	case verbose == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
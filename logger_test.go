@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		expected    []string
+	}{
+		{
+			description: "defaults to warnings",
+			args:        []string{"sub"},
+			expected:    []string{"level=WARN msg=warn", "level=ERROR msg=error"},
+		},
+		{
+			description: "verbose enables info",
+			args:        []string{"sub", "-v"},
+			expected:    []string{"level=INFO msg=info", "level=WARN msg=warn", "level=ERROR msg=error"},
+		},
+		{
+			description: "very verbose enables debug",
+			args:        []string{"-vv", "sub"},
+			expected:    []string{"level=DEBUG msg=debug", "level=INFO msg=info", "level=WARN msg=warn", "level=ERROR msg=error"},
+		},
+		{
+			description: "quiet only logs errors",
+			args:        []string{"sub", "--quiet", "-v"},
+			expected:    []string{"level=ERROR msg=error"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "root [flags] [command]",
+				Subcommands: []*cli.Command{
+					{
+						Usage: "sub",
+						Exec: func(c *cli.Context) error {
+							log := c.Logger()
+							log.Debug("debug")
+							log.Info("info")
+							log.Warn("warn")
+							log.Error("error")
+							return nil
+						},
+					},
+				},
+				Opts: cli.Options{
+					ErrWriter:      &b,
+					VerbosityFlags: true,
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+				got = append(got, line[strings.Index(line, "level="):])
+			}
+			eq(t, tc.expected, got)
+		})
+	}
+}
+
+func TestLogger_WithoutVerbosityFlags(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "root [flags]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "quiet", Usage: "Do not print the result"},
+			&cli.CountFlag{Name: "verbose, v", Usage: "Print more details"},
+		},
+		Exec: func(c *cli.Context) error {
+			log := c.Logger()
+			log.Info("info")
+			log.Warn("warn")
+			return nil
+		},
+		Opts: cli.Options{ErrWriter: &b},
+	}
+	eq(t, nil, c.Execute([]string{"--quiet"}))
+	eq(t, true, strings.Contains(b.String(), "level=WARN msg=warn"))
+
+	b.Reset()
+	eq(t, nil, c.Execute([]string{"-v"}))
+	eq(t, false, strings.Contains(b.String(), "level=INFO"))
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Migration rewrites the invocations of a command that has been moved or renamed (see Options.Migrations), so that
+// the old command layout keeps working while users migrate.
+type Migration struct {
+	// From is the old path of the command (without the name of the root command), e.g. "deploy-service".
+	From string
+
+	// To is the new path of the command (without the name of the root command), e.g. "service deploy".
+	To string
+
+	// Flags (optional) maps the old names of renamed flags to their new names, e.g. {"svc": "service"}. Only the long
+	// names of the flags given after the old path are renamed.
+	Flags map[string]string
+}
+
+// migrate rewrites the arguments if they invoke the old path of a migration, and prints a deprecation notice to the
+// ErrWriter. Migrations with longer paths take precedence.
+func (c *Command) migrate(args []string) []string {
+	migrations := append([]Migration(nil), c.Opts.Migrations...)
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return len(strings.Fields(migrations[i].From)) > len(strings.Fields(migrations[j].From))
+	})
+	for _, m := range migrations {
+		if rewritten, ok := m.rewrite(c, args); ok {
+			fmt.Fprintf(c.Opts.ErrWriter, c.tr("warning: %q is deprecated, use %q instead")+"\n",
+				c.name()+" "+m.From, c.name()+" "+m.To)
+			return rewritten
+		}
+	}
+	return args
+}
+
+// rewrite returns the rewritten arguments, or false if the arguments do not invoke the old path of the migration.
+// The positional arguments are found using the flags of the root command, since the old commands no longer exist.
+func (m Migration) rewrite(root *Command, args []string) ([]string, bool) {
+	from := strings.Fields(m.From)
+	if len(from) == 0 {
+		return nil, false
+	}
+	var indices []int
+	for offset := 0; len(indices) < len(from); {
+		i := positionalIndex(root.fs, args[offset:])
+		if i < 0 || args[offset+i] != from[len(indices)] {
+			return nil, false
+		}
+		indices = append(indices, offset+i)
+		offset += i + 1
+	}
+
+	var (
+		rewritten  []string
+		terminated bool
+	)
+	for i, arg := range args {
+		switch {
+		case i == indices[0]:
+			rewritten = append(rewritten, strings.Fields(m.To)...)
+		case containsIndex(indices, i):
+		case i > indices[0] && !terminated:
+			terminated = arg == "--" // Flags are not renamed after the terminator.
+			rewritten = append(rewritten, m.renameFlag(arg))
+		default:
+			rewritten = append(rewritten, arg)
+		}
+	}
+	return rewritten, true
+}
+
+// renameFlag returns the argument with the new name of the flag, if it is a long flag that has been renamed.
+func (m Migration) renameFlag(arg string) string {
+	if !strings.HasPrefix(arg, "--") || arg == "--" {
+		return arg
+	}
+	name, value, hasValue := strings.Cut(arg[2:], "=")
+	renamed, ok := m.Flags[name]
+	if !ok {
+		return arg
+	}
+	if hasValue {
+		return "--" + renamed + "=" + value
+	}
+	return "--" + renamed
+}
+
+// containsIndex returns true if i is one of the indices.
+func containsIndex(indices []int, i int) bool {
+	for _, index := range indices {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
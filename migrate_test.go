@@ -0,0 +1,95 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		description     string
+		args            []string
+		expectedPath    string
+		expectedService string
+		expectedArgs    []string
+		expectedWarning string
+	}{
+		{
+			description:     "moved command",
+			args:            []string{"deploy-service", "--svc", "api", "prod"},
+			expectedPath:    "service deploy",
+			expectedService: "api",
+			expectedArgs:    []string{"prod"},
+			expectedWarning: "warning: \"cloud deploy-service\" is deprecated, use \"cloud service deploy\" instead\n",
+		},
+		{
+			description:     "global flags before the old path",
+			args:            []string{"--region", "eu-west-1", "deploy-service", "--svc=api"},
+			expectedPath:    "service deploy",
+			expectedService: "api",
+			expectedWarning: "warning: \"cloud deploy-service\" is deprecated, use \"cloud service deploy\" instead\n",
+		},
+		{
+			description:     "nested old path",
+			args:            []string{"legacy", "deploy", "--service", "web", "--", "--svc"},
+			expectedPath:    "service deploy",
+			expectedService: "web",
+			expectedArgs:    []string{"--svc"},
+			expectedWarning: "warning: \"cloud legacy deploy\" is deprecated, use \"cloud service deploy\" instead\n",
+		},
+		{
+			description:     "new path",
+			args:            []string{"service", "deploy", "--service", "api"},
+			expectedPath:    "service deploy",
+			expectedService: "api",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				warnings bytes.Buffer
+				path     string
+				service  string
+				args     []string
+			)
+			c := cli.Command{
+				Usage: "cloud [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region"}},
+				Opts: cli.Options{
+					ErrWriter: &warnings,
+					Migrations: []cli.Migration{
+						{From: "deploy-service", To: "service deploy", Flags: map[string]string{"svc": "service"}},
+						{From: "legacy deploy", To: "service deploy"},
+					},
+				},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "service [command]",
+						Subcommands: []*cli.Command{
+							{
+								Usage: "deploy [flags] [env]",
+								Flags: []cli.Flag{&cli.StringFlag{Name: "service"}},
+								Exec: func(c *cli.Context) error {
+									path = "service deploy"
+									service, _ = c.GetString("service")
+									args = c.Args()
+									return nil
+								},
+							},
+						},
+					},
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, tc.expectedPath, path)
+			eq(t, tc.expectedService, service)
+			if len(tc.expectedArgs) > 0 {
+				eq(t, tc.expectedArgs, args)
+			}
+			eq(t, tc.expectedWarning, warnings.String())
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package onepassword provides a cli.FlagResolver that reads secrets using the 1Password CLI (op). Flags opt in to
+// the resolver by setting the Annotation to a secret reference:
+//
+//	&cli.StringFlag{
+//		Name:        "token",
+//		Annotations: map[string]string{onepassword.Annotation: "op://Engineering/API/token"},
+//	}
+//
+// The secrets are read with "op read", so the user must be signed in to the 1Password CLI (or have the desktop app
+// integration enabled).
+package onepassword
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Annotation is the key of the flag annotation that holds the secret reference for the flag, e.g.
+// "op://vault/item/field".
+const Annotation = "1password"
+
+// Resolver implements cli.FlagResolver by reading secrets with the 1Password CLI. Flags that cannot be resolved
+// because of an error (e.g. the user not being signed in) are treated as unresolved, and the last error is available
+// from Err.
+type Resolver struct {
+	// Path (optional) of the op executable. Defaults to "op" (found in the PATH).
+	Path string
+
+	// Account (optional) to read the secrets from, see "op read --account".
+	Account string
+
+	// Timeout (optional) for reading a secret. Defaults to 30 seconds, since op might prompt for authentication.
+	Timeout time.Duration
+
+	err error
+}
+
+var _ cli.NamedResolver = &Resolver{}
+var _ cli.FlagSource = &Resolver{}
+
+// Resolve implements cli.FlagResolver.
+func (r *Resolver) Resolve(flag cli.Flag) (string, bool) {
+	ref, ok := reference(flag)
+	if !ok {
+		return "", false
+	}
+	v, err := r.read(ref)
+	if err != nil {
+		r.err = fmt.Errorf("reading %q for flag %q: %w", ref, flag.GetName(), err)
+		return "", false
+	}
+	return v, true
+}
+
+// Name implements cli.NamedResolver.
+func (r *Resolver) Name() string {
+	return "1password"
+}
+
+// Source implements cli.FlagSource.
+func (r *Resolver) Source(flag cli.Flag) string {
+	ref, _ := reference(flag)
+	return ref
+}
+
+// Err returns the last error that occurred when resolving a flag.
+func (r *Resolver) Err() error {
+	return r.err
+}
+
+// reference returns the secret reference of the flag.
+func reference(flag cli.Flag) (string, bool) {
+	af, ok := flag.(cli.AnnotatedFlag)
+	if !ok {
+		return "", false
+	}
+	ref, ok := af.GetAnnotations()[Annotation]
+	return ref, ok && strings.HasPrefix(ref, "op://")
+}
+
+// read returns the secret for the reference using "op read".
+func (r *Resolver) read(ref string) (string, error) {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	path := r.Path
+	if path == "" {
+		path = "op"
+	}
+	args := []string{"read", "--no-newline"}
+	if r.Account != "" {
+		args = append(args, "--account", r.Account)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, append(args, ref)...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,65 @@
+package onepassword_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/onepassword"
+)
+
+// fakeOp is a stand-in for the op executable, which prints the arguments it was given for known references.
+const fakeOp = `#!/bin/sh
+for ref; do :; done
+if [ "$ref" = "op://Engineering/API/token" ]; then
+	printf '%s' "$*"
+	exit 0
+fi
+echo "[ERROR] could not read secret '$ref': item not found" >&2
+exit 1
+`
+
+func TestResolver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake op executable is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "op")
+	if err := os.WriteFile(path, []byte(fakeOp), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	resolver := &onepassword.Resolver{Path: path, Account: "my.1password.com"}
+
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "token", Annotations: map[string]string{onepassword.Annotation: "op://Engineering/API/token"}},
+			&cli.StringFlag{Name: "password", Annotations: map[string]string{onepassword.Annotation: "op://Engineering/DB/password"}},
+			&cli.StringFlag{Name: "region", Annotations: map[string]string{onepassword.Annotation: "eu-west-1"}},
+		},
+		Opts: cli.Options{Resolvers: []cli.FlagResolver{resolver}},
+		Exec: func(c *cli.Context) error {
+			token, _ := c.GetString("token")
+			if expected := "read --no-newline --account my.1password.com op://Engineering/API/token"; token != expected {
+				t.Errorf("expected %q, got %q", expected, token)
+			}
+			if source := c.FlagSource("token"); source != "op://Engineering/API/token" {
+				t.Errorf("unexpected source: %s", source)
+			}
+			for _, name := range []string{"password", "region"} {
+				if v, _ := c.GetString(name); v != "" {
+					t.Errorf("expected %s to be unresolved, got %q", name, v)
+				}
+			}
+			return nil
+		},
+	}
+	if err := c.Execute(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedErr := `reading "op://Engineering/DB/password" for flag "password": exit status 1: [ERROR] could not read secret 'op://Engineering/DB/password': item not found`
+	if err := resolver.Err(); err == nil || err.Error() != expectedErr {
+		t.Errorf("expected error %q, got %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by OutputFlag and Context.Print.
+const (
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputTemplate = "template"
+)
+
+var _ Flag = &OutputFlag{}
+
+// OutputFlag defines the standard --output/-o flag used by Context.Print to decide how values are rendered. The flag
+// accepts table, json, yaml or template=<go template>, and defaults to table.
+type OutputFlag struct {
+	Name     string
+	Usage    string
+	EnvVar   []string
+	Value    string
+	Required bool
+}
+
+// Apply implements Flag.
+func (f *OutputFlag) Apply(fs *pflag.FlagSet) {
+	v := outputValue(f.Value)
+	if v == "" {
+		v = OutputTable
+	}
+	fs.VarP(&v, f.GetName(), f.GetShorthand(), usageWithEnvVar(f.GetUsage(), f.GetEnvVar()))
+}
+
+// GetName implements Flag.
+func (f *OutputFlag) GetName() string {
+	if f.Name == "" {
+		return "output"
+	}
+	s, _ := splitFlagName(f.Name)
+	return s
+}
+
+// GetShorthand implements Flag.
+func (f *OutputFlag) GetShorthand() string {
+	if f.Name == "" {
+		return "o"
+	}
+	_, s := splitFlagName(f.Name)
+	return s
+}
+
+// GetUsage implements Flag.
+func (f *OutputFlag) GetUsage() string {
+	if f.Usage == "" {
+		return "Output format (table|json|yaml|template=<template>)"
+	}
+	return f.Usage
+}
+
+// GetEnvVar implements Flag.
+func (f *OutputFlag) GetEnvVar() []string {
+	return f.EnvVar
+}
+
+// IsRequired implements Flag.
+func (f *OutputFlag) IsRequired() bool {
+	return f.Required
+}
+
+// outputValue implements pflag.Value and validates the output format when it is set.
+type outputValue string
+
+// Set implements pflag.Value.
+func (v *outputValue) Set(s string) error {
+	format, _ := splitOutputFormat(s)
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML, OutputTemplate:
+	default:
+		return fmt.Errorf("unknown output format %q", s)
+	}
+	*v = outputValue(s)
+	return nil
+}
+
+// String implements pflag.Value.
+func (v *outputValue) String() string {
+	return string(*v)
+}
+
+// Type implements pflag.Value.
+func (v *outputValue) Type() string {
+	return "format"
+}
+
+// splitOutputFormat splits e.g. "template={{ .Name }}" into the format and its argument.
+func splitOutputFormat(s string) (format, arg string) {
+	if i := strings.Index(s, "="); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// Print renders v to the configured Writer using the format given by the OutputFlag of the command (or table if the
+// command does not define one). Tables are rendered from the exported fields of a struct or slice of structs, and
+// the column headers can be set using the `table` struct tag (use "-" to omit a field).
+func (c *Context) Print(v interface{}) error {
+	format, arg := splitOutputFormat(c.outputFormat())
+	w := c.cmd.options().Writer
+
+	switch format {
+	case OutputJSON, OutputYAML:
+		return encode(w, format, v)
+	case OutputTemplate:
+		t, err := template.New("output").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("parsing output template: %w", err)
+		}
+		if err := t.Execute(w, v); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	default:
+		return printTable(w, v)
+	}
+}
+
+// Printf formats according to a format specifier and writes to the configured Writer, see fmt.Printf. Use it
+// instead of fmt.Printf so that the output can be captured (e.g. in tests).
+func (c *Context) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(c.cmd.options().Writer, format, a...)
+}
+
+// Println writes the operands followed by a newline to the configured Writer, see fmt.Println.
+func (c *Context) Println(a ...interface{}) {
+	fmt.Fprintln(c.cmd.options().Writer, a...)
+}
+
+// encode writes v to w as indented JSON or YAML.
+func encode(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported encoding %q", format)
+	}
+}
+
+// outputFormat returns the value of the OutputFlag for the current command.
+func (c *Context) outputFormat() string {
+	return c.cmd.outputFormat()
+}
+
+// outputFormat returns the value of the OutputFlag for the command.
+func (c *Command) outputFormat() string {
+	f := c.lookupFlag(func(f Flag) bool {
+		_, ok := f.(*OutputFlag)
+		return ok
+	})
+	if f == nil {
+		return OutputTable
+	}
+	return f.Value.String()
+}
+
+// printTable writes a struct (or a slice of structs) as an aligned table, and other values as-is. The headers are
+// written for empty slices, and nil elements are skipped.
+func printTable(w io.Writer, v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	var (
+		t    reflect.Type
+		rows []reflect.Value
+	)
+	switch rv.Kind() {
+	case reflect.Struct:
+		t, rows = rv.Type(), []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		if t = rv.Type().Elem(); t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if row := reflect.Indirect(rv.Index(i)); row.IsValid() {
+				rows = append(rows, row)
+			}
+		}
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		_, err := fmt.Fprintln(w, v)
+		return err
+	}
+
+	var (
+		fields  []int
+		headers []string
+	)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // Unexported
+		}
+		header := field.Tag.Get("table")
+		if header == "-" {
+			continue
+		}
+		if header == "" {
+			header = strings.ToUpper(field.Name)
+		}
+		fields, headers = append(fields, i), append(headers, header)
+	}
+
+	table := NewTable(w, headers...)
+	for _, row := range rows {
+		values := make([]interface{}, len(fields))
+		for i, field := range fields {
+			values[i] = row.Field(field).Interface()
+		}
+		table.AddRow(values...)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,172 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/spf13/pflag"
+)
+
+type instance struct {
+	ID     string `json:"id" yaml:"id" table:"INSTANCE ID"`
+	Name   string `json:"name" yaml:"name"`
+	Secret string `json:"-" yaml:"-" table:"-"`
+}
+
+func TestPrint(t *testing.T) {
+	instances := []instance{
+		{ID: "i-1", Name: "web", Secret: "s1"},
+		{ID: "i-2", Name: "database", Secret: "s2"},
+	}
+
+	tests := []struct {
+		description string
+		args        []string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			description: "defaults to table",
+			expected:    "INSTANCE ID   NAME\ni-1           web\ni-2           database\n",
+		},
+		{
+			description: "json",
+			args:        []string{"--output", "json"},
+			expected:    "[\n  {\n    \"id\": \"i-1\",\n    \"name\": \"web\"\n  },\n  {\n    \"id\": \"i-2\",\n    \"name\": \"database\"\n  }\n]\n",
+		},
+		{
+			description: "yaml",
+			args:        []string{"-o", "yaml"},
+			expected:    "- id: i-1\n  name: web\n- id: i-2\n  name: database\n",
+		},
+		{
+			description: "template",
+			args:        []string{"-o", "template={{ range . }}{{ .Name }} {{ end }}"},
+			expected:    "web database \n",
+		},
+		{
+			description: "unknown format",
+			args:        []string{"-o", "xml"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "list [flags]",
+				Flags: []cli.Flag{
+					&cli.OutputFlag{},
+				},
+				Exec: func(c *cli.Context) error {
+					return c.Print(instances)
+				},
+				Opts: cli.Options{
+					Writer: &b,
+				},
+			}
+			err := c.Execute(tc.args)
+			eq(t, tc.expectedErr, err != nil)
+			eq(t, tc.expected, b.String())
+		})
+	}
+}
+
+func TestPrint_Table(t *testing.T) {
+	tests := []struct {
+		description string
+		value       interface{}
+		expected    string
+	}{
+		{
+			description: "pointers",
+			value:       []*instance{{ID: "i-1", Name: "web"}, nil, {ID: "i-2", Name: "database"}},
+			expected:    "INSTANCE ID   NAME\ni-1           web\ni-2           database\n",
+		},
+		{
+			description: "empty slice",
+			value:       []*instance{},
+			expected:    "INSTANCE ID   NAME\n",
+		},
+		{
+			description: "struct",
+			value:       &instance{ID: "i-1", Name: "web"},
+			expected:    "INSTANCE ID   NAME\ni-1           web\n",
+		},
+		{
+			description: "other values",
+			value:       []string{"a", "b"},
+			expected:    "[a b]\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "list",
+				Exec:  func(c *cli.Context) error { return c.Print(tc.value) },
+				Opts:  cli.Options{Writer: &b},
+			}
+			eq(t, nil, c.Execute(nil))
+			eq(t, tc.expected, b.String())
+		})
+	}
+}
+
+func TestOutputFlag_Shared(t *testing.T) {
+	var (
+		output = &cli.OutputFlag{}
+		b      bytes.Buffer
+	)
+	c := cli.Command{
+		Usage: "list [flags]",
+		Flags: []cli.Flag{output},
+		Exec:  func(c *cli.Context) error { return c.Print(instance{ID: "i-1", Name: "web"}) },
+		Opts:  cli.Options{Writer: &b},
+	}
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	output.Apply(fs)
+	eq(t, nil, fs.Parse([]string{"-o", "json"}))
+	eq(t, "", output.Value)
+
+	eq(t, nil, c.Execute(nil))
+	eq(t, "INSTANCE ID   NAME\ni-1           web\n", b.String())
+}
+
+func TestTable(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "list",
+		Exec: func(c *cli.Context) error {
+			table := c.Table("REGION", "WEIGHT")
+			table.AddRow("eu-north-1", 10)
+			table.AddRow("us-east-1", 3)
+			return table.Flush()
+		},
+		Opts: cli.Options{
+			Writer: &b,
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, "REGION       WEIGHT\neu-north-1   10\nus-east-1    3\n", b.String())
+}
+
+func TestPrintfAndPrintln(t *testing.T) {
+	var out, errOut bytes.Buffer
+	c := cli.Command{
+		Usage: "deploy [flags]",
+		Opts:  cli.Options{Writer: &out, ErrWriter: &errOut, Color: cli.ColorNever},
+		Exec: func(c *cli.Context) error {
+			c.Printf("deploying %d replicas", 3)
+			c.Println("...", "done")
+			c.Errorf("failed to tag %s", "i-1")
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, "deploying 3 replicas... done\n", out.String())
+	eq(t, "failed to tag i-1\n", errOut.String())
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultPager is the pager used when $PAGER is not set. The flags make less exit if the text fits on one screen
+// (-F), pass colors through (-R), and not clear the screen on exit (-X).
+const defaultPager = "less -FRX"
+
+// printHelp writes the usage of the command to the ErrWriter, using a pager if the help text is taller than the
+// terminal (see Options.Pager). The text is written directly if the pager cannot be started.
+func (c *Command) printHelp() {
+	opts := c.options()
+	text := opts.UsageFunc(c) + "\n"
+	if f, ok := opts.ErrWriter.(*os.File); ok && opts.Pager && needsPager(f, text) {
+		command := opts.PagerCommand
+		if command == "" {
+			command = opts.getenv("PAGER")
+		}
+		if err := runPager(command, f, text); err == nil {
+			return
+		}
+	}
+	fmt.Fprint(opts.ErrWriter, text)
+}
+
+// needsPager returns true if f is a terminal, and the text has more lines than fit in the terminal.
+func needsPager(f *os.File, text string) bool {
+	if !term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+	_, height, err := term.GetSize(int(f.Fd()))
+	return err == nil && strings.Count(text, "\n") > height
+}
+
+// runPager pipes the text through the pager command (or defaultPager) to w.
+func runPager(command string, w *os.File, text string) error {
+	if command == "" {
+		command = defaultPager
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return fmt.Errorf("invalid pager command %q", command)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = strings.NewReader(text), w, w
+	return cmd.Run()
+}
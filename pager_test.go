@@ -0,0 +1,22 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestPagerNotUsedForNonTerminal(t *testing.T) {
+	var help bytes.Buffer
+	c := cli.Command{
+		Usage: "deployer [command]",
+		Opts:  cli.Options{ErrWriter: &help, Pager: true, PagerCommand: "false"},
+		Subcommands: []*cli.Command{
+			{Usage: "deploy", Help: strings.Repeat("Long help.\n", 500), Exec: func(c *cli.Context) error { return nil }},
+		},
+	}
+	eq(t, nil, c.Execute([]string{"deploy", "--help"}))
+	eq(t, 500, strings.Count(help.String(), "Long help."))
+}
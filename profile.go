@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	cpuProfileFlagName = "cpuprofile"
+	memProfileFlagName = "memprofile"
+	traceFlagName      = "trace"
+)
+
+// hiddenFlag wraps a Flag to hide it from usage texts and docs.
+type hiddenFlag struct {
+	Flag
+}
+
+// Apply implements Flag.
+func (f hiddenFlag) Apply(fs *pflag.FlagSet) {
+	copyFlag(f.Flag).Apply(fs)
+	fs.MarkHidden(f.GetName())
+}
+
+// profilingFlags returns the (hidden) flags used to profile the execution of a command.
+func profilingFlags() []Flag {
+	return []Flag{
+		hiddenFlag{&StringFlag{Name: cpuProfileFlagName, Usage: "Write a CPU profile to file"}},
+		hiddenFlag{&StringFlag{Name: memProfileFlagName, Usage: "Write a memory profile to file"}},
+		hiddenFlag{&StringFlag{Name: traceFlagName, Usage: "Write an execution trace to file"}},
+	}
+}
+
+// startProfiling starts the profiles requested by the profiling flags, and returns a function that stops the profiles
+// and writes them to file.
+func (c *Command) startProfiling() (func() error, error) {
+	if !c.options().ProfilingFlags {
+		return func() error { return nil }, nil
+	}
+	var stops []func() error
+	stop := func() error {
+		var err error
+		for _, s := range stops {
+			if e := s(); e != nil && err == nil {
+				err = e
+			}
+		}
+		return err
+	}
+
+	if path, _ := c.fs.GetString(cpuProfileFlagName); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("creating cpu profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("starting cpu profile: %w", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if path, _ := c.fs.GetString(traceFlagName); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("creating trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stop()
+			return nil, fmt.Errorf("starting trace: %w", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	if path, _ := c.fs.GetString(memProfileFlagName); path != "" {
+		stops = append(stops, func() error {
+			f, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("creating memory profile: %w", err)
+			}
+			defer f.Close()
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				return fmt.Errorf("writing memory profile: %w", err)
+			}
+			return nil
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,39 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestProfilingFlags(t *testing.T) {
+	var (
+		b   bytes.Buffer
+		dir = t.TempDir()
+		cpu = filepath.Join(dir, "cpu.pprof")
+		mem = filepath.Join(dir, "mem.pprof")
+		out = filepath.Join(dir, "trace.out")
+	)
+	c := cli.Command{
+		Usage: "printer [flags]",
+		Opts:  cli.Options{ErrWriter: &b, ProfilingFlags: true},
+		Exec:  func(c *cli.Context) error { return nil },
+	}
+
+	eq(t, nil, c.Execute([]string{"--help"}))
+	eq(t, false, strings.Contains(b.String(), "profile"))
+	eq(t, false, strings.Contains(b.String(), "Flags:"))
+
+	eq(t, nil, c.Execute([]string{"--cpuprofile", cpu, "--memprofile", mem, "--trace", out}))
+	for _, path := range []string{cpu, mem, out} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("expected profile: %s", err)
+		}
+		eq(t, true, info.Size() > 0)
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	spinnerFrames = []string{"|", "/", "-", "\\"}
+
+	// spinnerInterval is how often the spinner is redrawn on a terminal.
+	spinnerInterval = 100 * time.Millisecond
+
+	// progressLogInterval is how often progress is logged when the ErrWriter is not a terminal.
+	progressLogInterval = 5 * time.Second
+)
+
+// Spinner shows that a long-running operation is in progress. On a terminal it draws an animated spinner, otherwise
+// it degrades to logging a plain line periodically.
+type Spinner struct {
+	w    io.Writer
+	msg  string
+	tty  bool
+	now  func() time.Time
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Spinner returns a Spinner that writes to the configured ErrWriter. Call Start to begin drawing it.
+func (c *Context) Spinner(msg string) *Spinner {
+	opts := c.cmd.options()
+	return &Spinner{w: opts.ErrWriter, msg: msg, tty: isTerminal(opts.ErrWriter), now: opts.now}
+}
+
+// Start draws the spinner until Stop is called.
+func (s *Spinner) Start() {
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+
+	interval := spinnerInterval
+	if !s.tty {
+		interval = progressLogInterval
+		fmt.Fprintf(s.w, "%s...\n", s.msg)
+	}
+	go func() {
+		defer s.wg.Done()
+		started := s.now()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			if s.tty {
+				fmt.Fprintf(s.w, "\r%s %s", spinnerFrames[i%len(spinnerFrames)], s.msg)
+			}
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				if !s.tty {
+					fmt.Fprintf(s.w, "%s... (%s)\n", s.msg, s.now().Sub(started).Round(time.Second))
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the spinner and writes the final message (if any) in its place.
+func (s *Spinner) Stop(final string) {
+	if s.stop != nil {
+		close(s.stop)
+		s.wg.Wait()
+		s.stop = nil
+	}
+	if s.tty {
+		fmt.Fprintf(s.w, "\r%s\r", strings.Repeat(" ", len(s.msg)+2))
+	}
+	if final != "" {
+		fmt.Fprintln(s.w, final)
+	}
+}
+
+// ProgressBar shows the progress of an operation with a known number of steps. On a terminal the bar is redrawn on
+// every update, otherwise progress is logged as plain lines at most every few seconds.
+type ProgressBar struct {
+	w        io.Writer
+	msg      string
+	tty      bool
+	total    int
+	current  int
+	logged   int
+	loggedAt time.Time
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// ProgressBar returns a ProgressBar for total steps that writes to the configured ErrWriter.
+func (c *Context) ProgressBar(msg string, total int) *ProgressBar {
+	opts := c.cmd.options()
+	return &ProgressBar{w: opts.ErrWriter, msg: msg, tty: isTerminal(opts.ErrWriter), total: total, now: opts.now}
+}
+
+// Add increments the progress by n steps.
+func (p *ProgressBar) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set(p.current + n)
+}
+
+// Set sets the progress to n steps.
+func (p *ProgressBar) Set(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set(n)
+}
+
+// Finish completes the progress bar.
+func (p *ProgressBar) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set(p.total)
+	if p.tty {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// set updates the progress and draws the bar.
+func (p *ProgressBar) set(n int) {
+	if n > p.total {
+		n = p.total
+	}
+	p.current = n
+
+	percent := 100
+	if p.total > 0 {
+		percent = p.current * 100 / p.total
+	}
+	if p.tty {
+		const width = 30
+		filled := width * percent / 100
+		fmt.Fprintf(p.w, "\r%s [%s%s] %d/%d", p.msg, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), p.current, p.total)
+		return
+	}
+	if p.current == p.logged {
+		return
+	}
+	if now := p.now(); p.current == p.total || now.Sub(p.loggedAt) >= progressLogInterval {
+		fmt.Fprintf(p.w, "%s: %d/%d (%d%%)\n", p.msg, p.current, p.total, percent)
+		p.logged, p.loggedAt = p.current, now
+	}
+}
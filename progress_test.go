@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestProgressWithoutTerminal(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "progress",
+		Exec: func(c *cli.Context) error {
+			s := c.Spinner("Waiting for instances")
+			s.Start()
+			s.Stop("Instances are ready")
+
+			p := c.ProgressBar("Uploading", 4)
+			for i := 0; i < 4; i++ {
+				p.Add(1)
+			}
+			p.Finish()
+			return nil
+		},
+		Opts: cli.Options{
+			ErrWriter: &b,
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, "Waiting for instances...\nInstances are ready\nUploading: 1/4 (25%)\nUploading: 4/4 (100%)\n", b.String())
+}
+
+func TestProgressWithoutTerminal_Clock(t *testing.T) {
+	var (
+		b   bytes.Buffer
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	c := cli.Command{
+		Usage: "progress",
+		Exec: func(c *cli.Context) error {
+			p := c.ProgressBar("Uploading", 6)
+			for i := 0; i < 6; i++ {
+				now = now.Add(3 * time.Second)
+				p.Add(1)
+			}
+			p.Finish()
+			return nil
+		},
+		Opts: cli.Options{
+			ErrWriter: &b,
+			Now:       func() time.Time { return now },
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, "Uploading: 1/6 (16%)\nUploading: 3/6 (50%)\nUploading: 5/6 (83%)\nUploading: 6/6 (100%)\n", b.String())
+}
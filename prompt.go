@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNoInput is returned by the prompt helpers when the Reader is exhausted before an answer was given.
+var ErrNoInput = errors.New("no input")
+
+// Confirm asks the user a yes/no question and returns true if the answer is yes. Anything other than "y" or "yes"
+// (case insensitive) is treated as no. Confirm returns true without prompting if YesFlag is set.
+func (c *Context) Confirm(msg string) (bool, error) {
+	if c.assumeYes() {
+		return true, nil
+	}
+	answer, err := c.Prompt(msg + " " + c.cmd.tr("[y/N]"))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Prompt writes the message to the configured ErrWriter and returns the line read from the configured Reader, with
+// surrounding whitespace removed. ErrNonInteractive is returned if YesFlag is set.
+func (c *Context) Prompt(msg string) (string, error) {
+	if c.assumeYes() {
+		return "", ErrNonInteractive
+	}
+	fmt.Fprintf(c.cmd.options().ErrWriter, "%s ", msg)
+	return c.readLine()
+}
+
+// Password works like Prompt, but does not echo the input when the configured Reader is a terminal.
+func (c *Context) Password(msg string) (string, error) {
+	if c.assumeYes() || !isTerminal(c.cmd.options().Reader) {
+		return c.Prompt(msg)
+	}
+	f := c.cmd.options().Reader.(*os.File)
+	fmt.Fprintf(c.cmd.options().ErrWriter, "%s ", msg)
+	b, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(c.cmd.options().ErrWriter)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Select asks the user to pick one of the given options, either by its number or by its value, and returns the
+// selected option. The question is repeated until a valid option is given. ErrNonInteractive is returned if YesFlag
+// is set.
+func (c *Context) Select(msg string, options []string) (string, error) {
+	if len(options) == 0 {
+		return "", errors.New("no options to select from")
+	}
+	if c.assumeYes() {
+		return "", ErrNonInteractive
+	}
+	for i, option := range options {
+		fmt.Fprintf(c.cmd.options().ErrWriter, "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := c.Prompt(fmt.Sprintf("%s [1-%d]:", msg, len(options)))
+		if err != nil {
+			return "", err
+		}
+		if i, err := strconv.Atoi(answer); err == nil && i > 0 && i <= len(options) {
+			return options[i-1], nil
+		}
+		for _, option := range options {
+			if answer == option {
+				return option, nil
+			}
+		}
+		fmt.Fprintf(c.cmd.options().ErrWriter, c.cmd.tr("invalid option %q")+"\n", answer)
+	}
+}
+
+// readLine reads a single line from the configured Reader. The buffered reader is kept on the Context so that
+// consecutive prompts do not lose input.
+func (c *Context) readLine() (string, error) {
+	line, err := c.stdin().ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			if line == "" {
+				return "", ErrNoInput
+			}
+		} else {
+			return "", err
+		}
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestPrompts(t *testing.T) {
+	var b bytes.Buffer
+	c := cli.Command{
+		Usage: "prompt",
+		Exec: func(c *cli.Context) error {
+			ok, err := c.Confirm("Delete 3 instances?")
+			eq(t, nil, err)
+			eq(t, true, ok)
+
+			name, err := c.Prompt("Name:")
+			eq(t, nil, err)
+			eq(t, "printer", name)
+
+			token, err := c.Password("Token:")
+			eq(t, nil, err)
+			eq(t, "secret", token)
+
+			region, err := c.Select("Region", []string{"eu-north-1", "us-east-1"})
+			eq(t, nil, err)
+			eq(t, "us-east-1", region)
+
+			_, err = c.Prompt("Again:")
+			eq(t, cli.ErrNoInput, err)
+			return nil
+		},
+		Opts: cli.Options{
+			Reader:    strings.NewReader("yes\n printer \nsecret\n3\n2\n"),
+			ErrWriter: &b,
+		},
+	}
+	eq(t, nil, c.Execute(nil))
+	eq(t, strings.Join([]string{
+		"Delete 3 instances? [y/N] Name: Token:   1) eu-north-1",
+		"  2) us-east-1",
+		"Region [1-2]: invalid option \"3\"",
+		"Region [1-2]: Again: ",
+	}, "\n"), b.String())
+}
+
+func TestPromptsWithYesFlag(t *testing.T) {
+	c := cli.Command{
+		Usage: "prompt [flags]",
+		Flags: []cli.Flag{
+			&cli.YesFlag{},
+		},
+		Exec: func(c *cli.Context) error {
+			eq(t, false, c.Interactive())
+
+			ok, err := c.Confirm("Delete 3 instances?")
+			eq(t, nil, err)
+			eq(t, true, ok)
+
+			_, err = c.Prompt("Name:")
+			eq(t, cli.ErrNonInteractive, err)
+
+			_, err = c.Select("Region", []string{"eu-north-1"})
+			eq(t, cli.ErrNonInteractive, err)
+			return nil
+		},
+		Opts: cli.Options{
+			Reader:    strings.NewReader(""),
+			ErrWriter: &bytes.Buffer{},
+		},
+	}
+	eq(t, nil, c.Execute([]string{"--yes"}))
+}
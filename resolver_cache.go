@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var _ PathResolver = &CachingResolver{}
+var _ NamedResolver = &CachingResolver{}
+var _ FlagSource = &CachingResolver{}
+
+// CachingResolver implements FlagResolver by memoizing the lookups of another resolver, so that slow (e.g. network)
+// resolvers are only used once per flag within the TTL. Use CachedResolver to create the resolver.
+type CachingResolver struct {
+	// Path (optional) of a file that the cache is persisted to, so that it is shared between invocations of the
+	// command. The file is written with 0600 permissions since it may contain secrets, and errors reading or writing
+	// it are ignored (i.e. the cache is only kept in memory).
+	Path string
+
+	// Now (optional) returns the current time, which decides if the cached lookups have expired. Defaults to the Now of
+	// the command Options (or time.Now, when the resolver is used directly).
+	Now func() time.Time
+
+	inner   FlagResolver
+	ttl     time.Duration
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]cacheEntry
+	used    map[string]string
+}
+
+// cacheEntry is a lookup that has been cached by CachingResolver.
+type cacheEntry struct {
+	Value   string    `json:"value"`
+	Found   bool      `json:"found"`
+	Source  string    `json:"source,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+// CachedResolver returns a CachingResolver that memoizes the lookups of the inner resolver for the ttl. Flags that
+// are not found are cached as well, unless the inner resolver reports an error from an Err() error method after the
+// lookup (like the remote resolvers in the subpackages of cli). The name (see NamedResolver) of the inner resolver is
+// kept, so flags can still opt out of it.
+func CachedResolver(inner FlagResolver, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{inner: inner, ttl: ttl}
+}
+
+// Resolve implements FlagResolver.
+func (r *CachingResolver) Resolve(flag Flag) (string, bool) {
+	return r.ResolvePath(nil, flag)
+}
+
+// ResolvePath implements PathResolver. The path is part of the cache key, and is passed on if the inner resolver is
+// a PathResolver.
+func (r *CachingResolver) ResolvePath(path []string, flag Flag) (string, bool) {
+	return r.resolve(path, flag, r.now)
+}
+
+// resolve implements ResolvePath, using now to decide if the cached lookups have expired.
+func (r *CachingResolver) resolve(path []string, flag Flag, now func() time.Time) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.Join(append(append([]string{}, path...), flag.GetName()), " ")
+	r.load(now)
+	if e, ok := r.entries[key]; ok && now().Before(e.Expires) {
+		if e.Found {
+			r.used[flag.GetName()] = key
+		}
+		return e.Value, e.Found
+	}
+
+	var (
+		v     string
+		found bool
+	)
+	if pr, ok := r.inner.(PathResolver); ok {
+		v, found = pr.ResolvePath(path, flag)
+	} else {
+		v, found = r.inner.Resolve(flag)
+	}
+	if er, ok := r.inner.(interface{ Err() error }); ok && !found && er.Err() != nil {
+		return "", false // The flag may not have been found because of the error.
+	}
+
+	e := cacheEntry{Value: v, Found: found, Expires: now().Add(r.ttl)}
+	if found {
+		e.Source = resolverSource(r.inner, flag)
+		r.used[flag.GetName()] = key
+	}
+	r.entries[key] = e
+	r.save()
+	return v, found
+}
+
+// Name implements NamedResolver.
+func (r *CachingResolver) Name() string {
+	return resolverName(r.inner)
+}
+
+// Source implements FlagSource, and returns the source that was given by the inner resolver when the value was
+// cached.
+func (r *CachingResolver) Source(flag Flag) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[r.used[flag.GetName()]]; ok && e.Source != "" {
+		return e.Source
+	}
+	return resolverSource(r.inner, flag)
+}
+
+// load reads the persisted cache (once), dropping entries that have expired.
+func (r *CachingResolver) load(now func() time.Time) {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+	r.entries = make(map[string]cacheEntry)
+	r.used = make(map[string]string)
+	if r.Path == "" {
+		return
+	}
+	b, err := ioutil.ReadFile(r.Path)
+	if err != nil {
+		return
+	}
+	var entries map[string]cacheEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return
+	}
+	t := now()
+	for k, e := range entries {
+		if t.Before(e.Expires) {
+			r.entries[k] = e
+		}
+	}
+}
+
+// save writes the cache to the Path (if set), replacing the file atomically so that concurrent invocations never
+// read a partial cache.
+func (r *CachingResolver) save() {
+	if r.Path == "" {
+		return
+	}
+	b, err := json.Marshal(r.entries)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o700); err != nil {
+		return
+	}
+	f, err := ioutil.TempFile(filepath.Dir(r.Path), filepath.Base(r.Path)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return
+	}
+	os.Rename(f.Name(), r.Path)
+}
+
+// now returns the current time (see Now).
+func (r *CachingResolver) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// optionsCachingResolver is a CachingResolver that uses the Now of the command Options, see Command.resolvers.
+type optionsCachingResolver struct {
+	*CachingResolver
+	now func() time.Time
+}
+
+// Resolve implements FlagResolver.
+func (r *optionsCachingResolver) Resolve(flag Flag) (string, bool) {
+	return r.resolve(nil, flag, r.now)
+}
+
+// ResolvePath implements PathResolver.
+func (r *optionsCachingResolver) ResolvePath(path []string, flag Flag) (string, bool) {
+	return r.resolve(path, flag, r.now)
+}
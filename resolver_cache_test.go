@@ -0,0 +1,142 @@
+package cli_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+// countingResolver counts the number of lookups for each flag.
+type countingResolver struct {
+	values  map[string]string
+	lookups map[string]int
+}
+
+func (r *countingResolver) Resolve(f cli.Flag) (string, bool) {
+	r.lookups[f.GetName()]++
+	v, ok := r.values[f.GetName()]
+	return v, ok
+}
+
+func (r *countingResolver) Name() string {
+	return "counting"
+}
+
+func (r *countingResolver) Source(f cli.Flag) string {
+	return "counting " + f.GetName()
+}
+
+// failingResolver never finds a flag, and always reports the same error.
+type failingResolver struct {
+	countingResolver
+}
+
+var errUnavailable = errors.New("unavailable")
+
+func (r *failingResolver) Resolve(f cli.Flag) (string, bool) {
+	r.lookups[f.GetName()]++
+	return "", false
+}
+
+func (r *failingResolver) Err() error {
+	return errUnavailable
+}
+
+func TestCachedResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "resolvers.json")
+	region := &cli.StringFlag{Name: "region"}
+	replicas := &cli.IntFlag{Name: "replicas"}
+
+	newResolver := func(ttl time.Duration) (*countingResolver, *cli.CachingResolver) {
+		inner := &countingResolver{values: map[string]string{"region": "eu-west-1"}, lookups: make(map[string]int)}
+		cached := cli.CachedResolver(inner, ttl)
+		cached.Path = path
+		return inner, cached
+	}
+
+	inner, cached := newResolver(time.Hour)
+	for i := 0; i < 3; i++ {
+		v, found := cached.Resolve(region)
+		eq(t, "eu-west-1", v)
+		eq(t, true, found)
+		_, found = cached.Resolve(replicas)
+		eq(t, false, found)
+	}
+	eq(t, map[string]int{"region": 1, "replicas": 1}, inner.lookups)
+	eq(t, "counting", cached.Name())
+	eq(t, "counting region", cached.Source(region))
+
+	t.Run("persisted", func(t *testing.T) {
+		inner, cached := newResolver(time.Hour)
+		v, found := cached.Resolve(region)
+		eq(t, "eu-west-1", v)
+		eq(t, true, found)
+		eq(t, "counting region", cached.Source(region))
+		eq(t, map[string]int{}, inner.lookups)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, cached := newResolver(time.Millisecond)
+		cached.Path = filepath.Join(t.TempDir(), "resolvers.json")
+		cached.Resolve(region)
+		time.Sleep(5 * time.Millisecond)
+
+		inner, expired := newResolver(time.Hour)
+		expired.Path = cached.Path
+		expired.Resolve(region)
+		eq(t, map[string]int{"region": 1}, inner.lookups)
+	})
+
+	t.Run("options clock", func(t *testing.T) {
+		inner, cached := newResolver(time.Hour)
+		cached.Path = filepath.Join(t.TempDir(), "resolvers.json")
+		now := time.Now().Add(-2 * time.Hour)
+		c := &cli.Command{
+			Usage: "app [flags]",
+			Flags: []cli.Flag{&cli.StringFlag{Name: "region"}},
+			Opts: cli.Options{
+				Resolvers: []cli.FlagResolver{cached},
+				Now:       func() time.Time { return now },
+			},
+			Exec: func(c *cli.Context) error { return nil },
+		}
+		eq(t, nil, c.Execute(nil))
+		now = now.Add(30 * time.Minute)
+		eq(t, nil, c.Execute(nil))
+		eq(t, map[string]int{"region": 1}, inner.lookups)
+
+		now = now.Add(time.Hour)
+		eq(t, nil, c.Execute(nil))
+		eq(t, map[string]int{"region": 2}, inner.lookups)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		inner := &failingResolver{countingResolver{lookups: make(map[string]int)}}
+		cached := cli.CachedResolver(inner, time.Hour)
+		for i := 0; i < 2; i++ {
+			_, found := cached.Resolve(region)
+			eq(t, false, found)
+		}
+		eq(t, map[string]int{"region": 2}, inner.lookups)
+	})
+
+	t.Run("command", func(t *testing.T) {
+		inner, cached := newResolver(time.Hour)
+		cached.Path = ""
+		c := &cli.Command{
+			Usage: "app [flags]",
+			Flags: []cli.Flag{&cli.StringFlag{Name: "region", DisableResolvers: []string{"counting"}}},
+			Opts:  cli.Options{Resolvers: []cli.FlagResolver{cached}},
+			Exec: func(c *cli.Context) error {
+				v, _ := c.GetString("region")
+				eq(t, "", v)
+				return nil
+			},
+		}
+		eq(t, nil, c.Execute(nil))
+		eq(t, map[string]int{}, inner.lookups)
+	})
+}
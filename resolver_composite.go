@@ -0,0 +1,69 @@
+package cli
+
+import "sync"
+
+// FirstOf returns a resolver that resolves flags using the first of the resolvers that resolves the flag, which can
+// be used to express layered configuration (together with Override). Flags that opt out of one of the resolvers (see
+// ResolverFlag) are not resolved by it, and a PathResolver is given the path of the command.
+func FirstOf(resolvers ...FlagResolver) FlagResolver {
+	return &compositeResolver{resolvers: resolvers, used: make(map[string]FlagResolver)}
+}
+
+// Override returns a resolver that resolves flags using the overlay, and falls back to the base for flags that are
+// not resolved by the overlay. E.g. a machine config overridden by a user config, overridden by the environment:
+//
+//	cli.Override(cli.Override(machineConfig, userConfig), &cli.EnvVarResolver{})
+func Override(base, overlay FlagResolver) FlagResolver {
+	return FirstOf(overlay, base)
+}
+
+var _ PathResolver = &compositeResolver{}
+var _ FlagSource = &compositeResolver{}
+
+// compositeResolver implements FirstOf.
+type compositeResolver struct {
+	resolvers []FlagResolver
+	mu        sync.Mutex
+	used      map[string]FlagResolver
+}
+
+// Resolve implements FlagResolver.
+func (r *compositeResolver) Resolve(flag Flag) (string, bool) {
+	return r.ResolvePath(nil, flag)
+}
+
+// ResolvePath implements PathResolver.
+func (r *compositeResolver) ResolvePath(path []string, flag Flag) (string, bool) {
+	for _, resolver := range r.resolvers {
+		if resolverDisabled(flag, resolver) {
+			continue
+		}
+		var (
+			v     string
+			found bool
+		)
+		if pr, ok := resolver.(PathResolver); ok {
+			v, found = pr.ResolvePath(path, flag)
+		} else {
+			v, found = resolver.Resolve(flag)
+		}
+		if found {
+			r.mu.Lock()
+			r.used[flag.GetName()] = resolver
+			r.mu.Unlock()
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Source implements FlagSource, and returns the source given by the resolver that resolved the flag.
+func (r *compositeResolver) Source(flag Flag) string {
+	r.mu.Lock()
+	resolver, ok := r.used[flag.GetName()]
+	r.mu.Unlock()
+	if !ok {
+		return ""
+	}
+	return resolverSource(resolver, flag)
+}
@@ -0,0 +1,67 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestCompositeResolvers(t *testing.T) {
+	var (
+		machine = staticResolver{"region": "eu-west-1", "replicas": "3", "profile": "default"}
+		user    = staticResolver{"region": "us-east-1", "profile": "dev"}
+		env     = staticResolver{"profile": "prod"}
+	)
+
+	tests := []struct {
+		description string
+		resolver    cli.FlagResolver
+		disable     []string
+		expected    map[string]string
+	}{
+		{
+			description: "first of",
+			resolver:    cli.FirstOf(user, machine),
+			expected:    map[string]string{"region": "us-east-1", "replicas": "3", "profile": "dev"},
+		},
+		{
+			description: "override",
+			resolver:    cli.Override(cli.Override(machine, user), env),
+			expected:    map[string]string{"region": "us-east-1", "replicas": "3", "profile": "prod"},
+		},
+		{
+			description: "disabled resolvers are skipped",
+			resolver:    cli.FirstOf(&cli.EnvVarResolver{}, machine),
+			disable:     []string{"cli_test.staticResolver"},
+			expected:    map[string]string{"region": "", "replicas": "", "profile": ""},
+		},
+		{
+			description: "empty",
+			resolver:    cli.FirstOf(),
+			expected:    map[string]string{"region": "", "replicas": "", "profile": ""},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var flags []cli.Flag
+			for _, name := range []string{"region", "replicas", "profile"} {
+				flags = append(flags, &cli.StringFlag{Name: name, DisableResolvers: tc.disable})
+			}
+			c := &cli.Command{
+				Usage: "app [flags]",
+				Flags: flags,
+				Opts:  cli.Options{Resolvers: []cli.FlagResolver{tc.resolver}},
+				Exec: func(c *cli.Context) error {
+					got := make(map[string]string)
+					for name := range tc.expected {
+						got[name], _ = c.GetString(name)
+					}
+					eq(t, tc.expected, got)
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(nil))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// valueReference matches a ${name} reference in a resolved value.
+var valueReference = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// valueExpander expands references in resolved values (see Options.ExpandResolvedValues), where references to flags
+// that have been resolved are expanded recursively.
+type valueExpander struct {
+	fs       *pflag.FlagSet
+	env      func(string) (string, bool)
+	resolved map[string]string
+	expanded map[string]string
+	visiting []string
+}
+
+// newValueExpander returns a valueExpander for the resolved values.
+func newValueExpander(fs *pflag.FlagSet, resolved []resolvedValue, env func(string) (string, bool)) *valueExpander {
+	e := &valueExpander{fs: fs, env: env, resolved: make(map[string]string), expanded: make(map[string]string)}
+	for _, r := range resolved {
+		e.resolved[r.flag.GetName()] = r.value
+	}
+	return e
+}
+
+// expand returns the expanded value of the resolved flag, or an error if the references of the flag form a cycle.
+func (e *valueExpander) expand(name string) (string, error) {
+	if v, ok := e.expanded[name]; ok {
+		return v, nil
+	}
+	for i, n := range e.visiting {
+		if n == name {
+			cycle := append(append([]string{}, e.visiting[i:]...), name)
+			return "", fmt.Errorf("expanding flag %q: reference cycle %s", e.visiting[0], strings.Join(cycle, " -> "))
+		}
+	}
+	e.visiting = append(e.visiting, name)
+	defer func() { e.visiting = e.visiting[:len(e.visiting)-1] }()
+
+	var err error
+	v := valueReference.ReplaceAllStringFunc(e.resolved[name], func(ref string) string {
+		ref = valueReference.FindStringSubmatch(ref)[1]
+		if err != nil {
+			return ""
+		}
+		var v string
+		v, err = e.lookup(ref)
+		return v
+	})
+	if err != nil {
+		return "", err
+	}
+	e.expanded[name] = v
+	return v, nil
+}
+
+// lookup returns the value of a reference, which is either a flag or an environment variable.
+func (e *valueExpander) lookup(ref string) (string, error) {
+	if _, ok := e.resolved[ref]; ok {
+		return e.expand(ref)
+	}
+	if f := e.fs.Lookup(ref); f != nil {
+		return f.Value.String(), nil
+	}
+	v, _ := e.env(ref)
+	return v, nil
+}
@@ -0,0 +1,88 @@
+package cli_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestExpandResolvedValues(t *testing.T) {
+	os.Setenv("CLI_TEST_DOMAIN", "example.com")
+	defer os.Unsetenv("CLI_TEST_DOMAIN")
+
+	tests := []struct {
+		description string
+		args        []string
+		values      staticResolver
+		disable     bool
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			description: "flags and environment variables",
+			values:      staticResolver{"endpoint": "https://${region}.api.${CLI_TEST_DOMAIN}", "region": "eu-west-1"},
+			expected:    map[string]string{"endpoint": "https://eu-west-1.api.example.com", "region": "eu-west-1"},
+		},
+		{
+			description: "nested references",
+			values:      staticResolver{"endpoint": "https://${host}", "host": "${region}.${CLI_TEST_DOMAIN}"},
+			expected:    map[string]string{"endpoint": "https://us-east-1.example.com", "host": "us-east-1.example.com"},
+		},
+		{
+			description: "values from arguments are used but not expanded",
+			args:        []string{"--region", "${CLI_TEST_DOMAIN}"},
+			values:      staticResolver{"endpoint": "https://${region}"},
+			expected:    map[string]string{"endpoint": "https://${CLI_TEST_DOMAIN}", "region": "${CLI_TEST_DOMAIN}"},
+		},
+		{
+			description: "undefined references",
+			values:      staticResolver{"endpoint": "https://${CLI_TEST_UNDEFINED}api"},
+			expected:    map[string]string{"endpoint": "https://api"},
+		},
+		{
+			description: "disabled",
+			values:      staticResolver{"endpoint": "https://${region}"},
+			disable:     true,
+			expected:    map[string]string{"endpoint": "https://${region}"},
+		},
+		{
+			description: "cycle",
+			values:      staticResolver{"endpoint": "${host}", "host": "${endpoint}"},
+			expectedErr: `parsing command: expanding flag "endpoint": reference cycle endpoint -> host -> endpoint`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &cli.Command{
+				Usage: "app [flags]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "endpoint"},
+					&cli.StringFlag{Name: "host"},
+					&cli.StringFlag{Name: "region", Value: "us-east-1"},
+				},
+				Opts: cli.Options{
+					Resolvers:            []cli.FlagResolver{tc.values},
+					ExpandResolvedValues: !tc.disable,
+				},
+				Exec: func(c *cli.Context) error {
+					for name, expected := range tc.expected {
+						v, _ := c.GetString(name)
+						eq(t, expected, v)
+					}
+					return nil
+				},
+			}
+			err := c.Execute(tc.args)
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				eq(t, tc.expectedErr, err.Error())
+				return
+			}
+			eq(t, nil, err)
+		})
+	}
+}
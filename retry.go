@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry is used to retry the Exec of a command on errors, with exponential backoff and jitter. Attach it to a command
+// by wrapping the Exec:
+//
+//	Exec: cli.Retry{Attempts: 5}.Wrap(func(c *cli.Context) error { ... })
+type Retry struct {
+	// Attempts is the maximum number of attempts (including the first). Defaults to 3.
+	Attempts int
+
+	// Retryable (optional) decides if an error should be retried. Defaults to retrying all errors.
+	Retryable func(error) bool
+
+	// InitialBackoff is the backoff before the first retry, which is doubled for each attempt. Defaults to 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff between attempts. Defaults to 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// Wrap returns exec wrapped to retry errors according to the Retry. Failed attempts are logged to the ErrWriter.
+func (r Retry) Wrap(exec func(*Context) error) func(*Context) error {
+	if r.Attempts <= 0 {
+		r.Attempts = 3
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = time.Second
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 30 * time.Second
+	}
+	return func(c *Context) error {
+		backoff := r.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := exec(c)
+			if err == nil || attempt >= r.Attempts || (r.Retryable != nil && !r.Retryable(err)) {
+				return err
+			}
+			// Jitter the backoff, i.e. wait a random duration between half and all of the backoff.
+			wait := backoff/2 + time.Duration(c.cmd.options().int63n(int64(backoff/2)+1))
+			fmt.Fprintf(c.cmd.options().ErrWriter, "attempt %d/%d failed: %s (retrying in %s)\n", attempt, r.Attempts, err, wait.Round(time.Millisecond))
+			time.Sleep(wait)
+
+			if backoff *= 2; backoff > r.MaxBackoff {
+				backoff = r.MaxBackoff
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestRetry(t *testing.T) {
+	var (
+		flaky     = errors.New("eventually consistent")
+		permanent = errors.New("permanent")
+	)
+	tests := []struct {
+		description      string
+		errs             []error
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{
+			description:      "succeeds after retries",
+			errs:             []error{flaky, flaky, nil},
+			expectedAttempts: 3,
+		},
+		{
+			description:      "gives up after attempts",
+			errs:             []error{flaky, flaky, flaky, nil},
+			expectedErr:      flaky,
+			expectedAttempts: 3,
+		},
+		{
+			description:      "does not retry errors that are not retryable",
+			errs:             []error{permanent, nil},
+			expectedErr:      permanent,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				b        bytes.Buffer
+				attempts int
+			)
+			retry := cli.Retry{
+				Attempts:       3,
+				Retryable:      func(err error) bool { return errors.Is(err, flaky) },
+				InitialBackoff: time.Millisecond,
+			}
+			c := cli.Command{
+				Usage: "sync",
+				Opts:  cli.Options{ErrWriter: &b},
+				Exec: retry.Wrap(func(c *cli.Context) error {
+					err := tc.errs[attempts]
+					attempts++
+					return err
+				}),
+			}
+			eq(t, tc.expectedErr, c.Execute([]string{}))
+			eq(t, tc.expectedAttempts, attempts)
+			eq(t, tc.expectedAttempts-1, strings.Count(b.String(), "failed: eventually consistent (retrying in"))
+		})
+	}
+}
+
+func TestRetry_Rand(t *testing.T) {
+	run := func() string {
+		var b bytes.Buffer
+		retry := cli.Retry{Attempts: 3, InitialBackoff: 20 * time.Millisecond}
+		c := cli.Command{
+			Usage: "sync",
+			Opts:  cli.Options{ErrWriter: &b, Rand: rand.New(rand.NewSource(1))},
+			Exec: retry.Wrap(func(c *cli.Context) error {
+				return errors.New("failed")
+			}),
+		}
+		c.Execute([]string{})
+		return b.String()
+	}
+	eq(t, run(), run())
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// translateSlashFlags translates Windows-style flags into long flags (see Options.AllowSlashFlags). Values of flags
+// (e.g. "--path /tmp") and arguments after the "--" terminator are never translated.
+func (c *Command) translateSlashFlags(args []string) []string {
+	var (
+		fs         = c.treeFlagSet()
+		translated = make([]string, 0, len(args))
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(translated, args[i:]...)
+		}
+		arg = c.translateSlashFlag(arg)
+		translated = append(translated, arg)
+		if !strings.HasPrefix(arg, "-") || len(arg) < 2 {
+			continue
+		}
+		if n := flagArgs(fs, append([]string{arg}, args[i+1:]...)); n > 1 {
+			translated = append(translated, args[i+1])
+			i++
+		}
+	}
+	return translated
+}
+
+// treeFlagSet returns a flag set with the flags of every command in the tree, so that the values of flags can be
+// skipped before the subcommand is known. The first flag with a name (or shorthand) wins.
+func (c *Command) treeFlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	c.addTreeFlags(fs)
+	return fs
+}
+
+// addTreeFlags implements treeFlagSet.
+func (c *Command) addTreeFlags(fs *pflag.FlagSet) {
+	for _, f := range c.declaredFlags() {
+		if fs.Lookup(f.GetName()) != nil {
+			continue
+		}
+		newFS([]Flag{f}).VisitAll(func(pf *pflag.Flag) {
+			if pf.Shorthand != "" && fs.ShorthandLookup(pf.Shorthand) != nil {
+				pf.Shorthand = ""
+			}
+			fs.AddFlag(pf)
+		})
+	}
+	for _, subcommand := range c.Subcommands {
+		subcommand.addTreeFlags(fs)
+	}
+}
+
+// translateSlashFlag translates "/name" or "/name:value" into "--name" or "--name=value", where name is the long
+// name or shorthand of a flag in the command tree, or returns the argument as-is.
+func (c *Command) translateSlashFlag(arg string) string {
+	if !strings.HasPrefix(arg, "/") {
+		return arg
+	}
+	name, value, hasValue := strings.Cut(arg[1:], ":")
+	if name = c.slashFlagName(name); name == "" {
+		return arg
+	}
+	if hasValue {
+		return "--" + name + "=" + value
+	}
+	return "--" + name
+}
+
+// slashFlagName returns the long name of the flag in the command tree with the given name or shorthand, or an empty
+// string if there is no such flag.
+func (c *Command) slashFlagName(name string) string {
+	if name == "" {
+		return ""
+	}
+	if help := c.helpFlag(); help.name != "" && (name == "?" || name == help.name || name == help.shorthand) {
+		return help.name
+	}
+	if f := c.findTreeFlag(name); f != nil {
+		return f.GetName()
+	}
+	return c.findTreeShorthand(name)
+}
+
+// findTreeShorthand returns the long name of the first flag in the command tree with the shorthand, or an empty
+// string if there is no such flag.
+func (c *Command) findTreeShorthand(shorthand string) string {
+	for _, f := range c.declaredFlags() {
+		if f.GetShorthand() == shorthand {
+			return f.GetName()
+		}
+	}
+	for _, subcommand := range c.Subcommands {
+		if name := subcommand.findTreeShorthand(shorthand); name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestAllowSlashFlags(t *testing.T) {
+	tests := []struct {
+		description    string
+		args           []string
+		disabled       bool
+		expectedRegion string
+		expectedForce  bool
+		expectedPath   string
+		expectedArgs   []string
+		expectedHelp   bool
+		expectedErr    bool
+	}{
+		{
+			description:    "long flags",
+			args:           []string{"/region:eu-west-1", "deploy", "/force"},
+			expectedRegion: "eu-west-1",
+			expectedForce:  true,
+		},
+		{
+			description:   "shorthand",
+			args:          []string{"deploy", "/f"},
+			expectedForce: true,
+		},
+		{
+			description:   "bool with value",
+			args:          []string{"deploy", "/force:false"},
+			expectedForce: false,
+		},
+		{
+			description:  "paths are left as-is",
+			args:         []string{"deploy", "/tmp/app", "/dist"},
+			expectedArgs: []string{"/tmp/app", "/dist"},
+		},
+		{
+			description:   "flag values are left as-is",
+			args:          []string{"deploy", "--path", "/tmp", "/f"},
+			expectedPath:  "/tmp",
+			expectedForce: true,
+		},
+		{
+			description:  "values of slash flags are left as-is",
+			args:         []string{"/path", "/tmp", "deploy", "/dist"},
+			expectedPath: "/tmp",
+			expectedArgs: []string{"/dist"},
+		},
+		{
+			description:  "arguments after terminator",
+			args:         []string{"deploy", "--", "/force"},
+			expectedArgs: []string{"/force"},
+		},
+		{
+			description:  "disabled",
+			args:         []string{"deploy", "/force"},
+			disabled:     true,
+			expectedArgs: []string{"/force"},
+		},
+		{
+			description:  "help",
+			args:         []string{"deploy", "/?"},
+			expectedHelp: true,
+		},
+		{
+			description: "invalid value",
+			args:        []string{"deploy", "/force:maybe"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				output bytes.Buffer
+				region string
+				force  bool
+				path   string
+				args   []string
+			)
+			c := cli.Command{
+				Usage: "app [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region"}, &cli.StringFlag{Name: "path"}, &cli.BoolFlag{Name: "tmp"}},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "deploy [flags] [paths...]",
+						Flags: []cli.Flag{&cli.BoolFlag{Name: "force, f"}},
+						Exec: func(c *cli.Context) error {
+							region, _ = c.GetString("region")
+							force, _ = c.GetBool("force")
+							path, _ = c.GetString("path")
+							args = c.Args()
+							return nil
+						},
+					},
+				},
+				Opts: cli.Options{AllowSlashFlags: !tc.disabled, Writer: &output, ErrWriter: &output},
+			}
+			err := c.Execute(tc.args)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			eq(t, tc.expectedRegion, region)
+			eq(t, tc.expectedForce, force)
+			eq(t, tc.expectedPath, path)
+			eq(t, tc.expectedArgs, append([]string(nil), args...))
+			eq(t, tc.expectedHelp, strings.Contains(output.String(), "Usage:"))
+		})
+	}
+}
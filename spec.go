@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"io"
+	"strings"
+)
+
+// Spec is a machine-readable description of a command and its subcommands.
+type Spec struct {
+	Name        string      `json:"name" yaml:"name"`
+	Path        string      `json:"path" yaml:"path"`
+	Usage       string      `json:"usage" yaml:"usage"`
+	Args        string      `json:"args,omitempty" yaml:"args,omitempty"`
+	ValidArgs   []string    `json:"validArgs,omitempty" yaml:"validArgs,omitempty"`
+	Help        string      `json:"help,omitempty" yaml:"help,omitempty"`
+	Examples    string      `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Flags       []*FlagSpec `json:"flags,omitempty" yaml:"flags,omitempty"`
+	GlobalFlags []*FlagSpec `json:"globalFlags,omitempty" yaml:"globalFlags,omitempty"`
+	Subcommands []*Spec     `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// FlagSpec is a machine-readable description of a flag.
+type FlagSpec struct {
+	Name      string   `json:"name" yaml:"name"`
+	Shorthand string   `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Type      string   `json:"type" yaml:"type"`
+	Default   string   `json:"default,omitempty" yaml:"default,omitempty"`
+	Usage     string   `json:"usage,omitempty" yaml:"usage,omitempty"`
+	EnvVar    []string `json:"envVar,omitempty" yaml:"envVar,omitempty"`
+	Required  bool     `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// RequiredIf is the condition for when the flag is required (see ConditionalFlag).
+	RequiredIf string `json:"requiredIf,omitempty" yaml:"requiredIf,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// NewSpec returns the Spec for the entire command tree of c.
+func NewSpec(c *Command) (*Spec, error) {
+	if err := c.initializeTree(); err != nil {
+		return nil, err
+	}
+	return newSpec(c), nil
+}
+
+// ExportSpec writes the Spec for the command tree of c to w, encoded as OutputJSON or OutputYAML.
+func ExportSpec(c *Command, w io.Writer, format string) error {
+	spec, err := NewSpec(c)
+	if err != nil {
+		return err
+	}
+	return encode(w, format, spec)
+}
+
+// newSpec returns the spec of an initialized command tree.
+func newSpec(c *Command) *Spec {
+	s := &Spec{
+		Name:        c.name(),
+		Path:        c.path(),
+		Usage:       c.usage(),
+		Args:        c.args(),
+		ValidArgs:   c.ValidArgs,
+		Help:        c.Help,
+		Examples:    c.Examples,
+		Flags:       newFlagSpecs(c, c.visibleFlags(c.LocalFlags())),
+		GlobalFlags: newFlagSpecs(c, c.visibleFlags(c.GlobalFlags())),
+		Annotations: c.Annotations,
+	}
+	for _, subcommand := range c.visibleSubcommands() {
+		s.Subcommands = append(s.Subcommands, newSpec(subcommand))
+	}
+	return s
+}
+
+// newFlagSpecs returns the specs for the given flags.
+func newFlagSpecs(c *Command, flags []Flag) []*FlagSpec {
+	fs := c.flagSet(flags)
+
+	var specs []*FlagSpec
+	for _, f := range flags {
+		pf := fs.Lookup(f.GetName())
+		specs = append(specs, &FlagSpec{
+			Name:      f.GetName(),
+			Shorthand: f.GetShorthand(),
+			Type:      pf.Value.Type(),
+			Default:   pf.DefValue,
+			Usage:     f.GetUsage(),
+			EnvVar:    f.GetEnvVar(),
+			Required:  c.isRequired(f),
+		})
+		if cf, ok := f.(ConditionalFlag); ok {
+			specs[len(specs)-1].RequiredIf = cf.GetRequiredIf()
+		}
+		if af, ok := f.(AnnotatedFlag); ok {
+			specs[len(specs)-1].Annotations = af.GetAnnotations()
+		}
+	}
+	return specs
+}
+
+// args returns the argument synopsis of the command, i.e. the ArgsUsage or the Usage without the command name. For
+// commands with subcommands, "[command]" and "[flags]" (if there are visible flags) are appended to the Usage when it
+// does not mention them (in English or as translated by the Translator). The ArgsUsage is always used as-is.
+func (c *Command) args() string {
+	if c.ArgsUsage != "" {
+		return c.ArgsUsage
+	}
+	var synopsis string
+	if i := strings.Index(c.Usage, " "); i >= 0 {
+		synopsis = strings.TrimSpace(c.Usage[i:])
+	}
+	if len(c.Subcommands) == 0 {
+		return synopsis
+	}
+	command := c.tr("[command]")
+	if mentioned := strings.Contains(synopsis, "command") || strings.Contains(synopsis, command); !mentioned {
+		synopsis += " " + command
+	}
+	flags := c.tr("[flags]")
+	if mentioned := strings.Contains(synopsis, "flags") || strings.Contains(synopsis, flags); !mentioned &&
+		len(c.visibleFlags(c.CombinedFlags())) > 0 {
+		synopsis += " " + flags
+	}
+	return strings.TrimSpace(synopsis)
+}
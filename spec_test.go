@@ -0,0 +1,65 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestExportSpec(t *testing.T) {
+	var b bytes.Buffer
+	eq(t, nil, cli.ExportSpec(newDocsCommand(), &b, cli.OutputYAML))
+	eq(t, `name: printer
+path: printer
+usage: printer [flags] [command]
+args: '[flags] [command]'
+help: Print things
+flags:
+  - name: debug
+    shorthand: d
+    type: bool
+    default: "false"
+    usage: Enable debug logging
+subcommands:
+  - name: repeat
+    path: printer repeat
+    usage: printer repeat [flags] <arg>
+    args: '[flags] <arg>'
+    help: Repeatedly print the given argument
+    examples: printer repeat --times 5 hello
+    flags:
+      - name: times
+        shorthand: t
+        type: int
+        default: "3"
+        usage: Number of times to print the argument
+        envVar:
+          - PRINTER_REPEAT_TIMES
+      - name: delimiter
+        type: string
+        usage: Delimiter to use when printing
+        required: true
+    globalFlags:
+      - name: debug
+        shorthand: d
+        type: bool
+        default: "false"
+        usage: Enable debug logging
+`, b.String())
+}
+
+func TestSpecAnnotations(t *testing.T) {
+	c := &cli.Command{
+		Usage:       "deploy [flags]",
+		Annotations: map[string]string{"owner": "platform"},
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region", Annotations: map[string]string{"policy": "eu-only"}},
+		},
+		Exec: func(c *cli.Context) error { return nil },
+	}
+	spec, err := cli.NewSpec(c)
+	eq(t, nil, err)
+	eq(t, map[string]string{"owner": "platform"}, spec.Annotations)
+	eq(t, map[string]string{"policy": "eu-only"}, spec.Flags[0].Annotations)
+}
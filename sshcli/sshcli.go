@@ -0,0 +1,47 @@
+// Package sshcli serves a cli.Command tree over SSH, which can be used to embed an admin CLI in a service. Each
+// session executes a clone of the command with the arguments and streams of the session. The package does not depend
+// on an SSH library, but the sessions of github.com/gliderlabs/ssh implement Session:
+//
+//	ssh.Handle(func(s ssh.Session) {
+//		sshcli.Serve(s, adminCmd)
+//	})
+package sshcli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/itsdalmo/cli"
+)
+
+// Session is an SSH session, e.g. a github.com/gliderlabs/ssh.Session. Reading and writing uses the standard input
+// and output of the session.
+type Session interface {
+	io.ReadWriter
+
+	// Stderr returns the standard error of the session.
+	Stderr() io.ReadWriter
+
+	// Command returns the arguments given by the client, e.g. ["deploy", "--dry-run"] for "ssh admin@host deploy
+	// --dry-run".
+	Command() []string
+
+	// Exit sends the exit status to the client and closes the session.
+	Exit(code int) error
+}
+
+// Serve executes a clone of the command (see cli.Command.Clone) with the arguments of the session, using the
+// streams of the session as the Reader, Writer and ErrWriter. Errors are written to the standard error of the
+// session, and the session is closed with the exit code of the error (see cli.ExitCode), which is also returned.
+func Serve(s Session, cmd *cli.Command) int {
+	c := cmd.Clone()
+	c.Opts.Reader, c.Opts.Writer, c.Opts.ErrWriter = s, s, s.Stderr()
+
+	err := c.Execute(s.Command())
+	if err != nil {
+		fmt.Fprintln(s.Stderr(), err)
+	}
+	code := cli.ExitCode(err)
+	s.Exit(code)
+	return code
+}
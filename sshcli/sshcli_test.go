@@ -0,0 +1,94 @@
+package sshcli_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+	"github.com/itsdalmo/cli/sshcli"
+)
+
+// session implements sshcli.Session.
+type session struct {
+	io.Reader
+	bytes.Buffer
+	stderr bytes.Buffer
+	args   []string
+	code   int
+}
+
+func (s *session) Read(p []byte) (int, error) { return s.Reader.Read(p) }
+func (s *session) Stderr() io.ReadWriter      { return &s.stderr }
+func (s *session) Command() []string          { return s.args }
+func (s *session) Exit(code int) error        { s.code = code; return nil }
+
+func TestServe(t *testing.T) {
+	cmd := &cli.Command{
+		Usage: "admin [command]",
+		Subcommands: []*cli.Command{
+			{
+				Usage: "greet [flags] <name>",
+				Exec: func(c *cli.Context) error {
+					msg, err := c.Prompt("Message:")
+					if err != nil {
+						return err
+					}
+					c.Printf("%s, %s!\n", msg, c.Arg(0))
+					return nil
+				},
+			},
+			{
+				Usage: "fail",
+				Exec: func(c *cli.Context) error {
+					return &cli.ExitError{Code: 3, Err: errors.New("something failed")}
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		description    string
+		args           []string
+		expectedCode   int
+		expectedOut    string
+		expectedStderr string
+	}{
+		{
+			description: "executes the command",
+			args:        []string{"greet", "world"},
+			expectedOut: "Hello, world!\n",
+		},
+		{
+			description:    "exit code",
+			args:           []string{"fail"},
+			expectedCode:   3,
+			expectedStderr: "something failed\n",
+		},
+		{
+			description:    "parse error",
+			args:           []string{"greet", "--unknown"},
+			expectedCode:   1,
+			expectedStderr: "parsing command: unknown flag: --unknown\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			s := &session{Reader: strings.NewReader("Hello\n"), args: tc.args}
+			code := sshcli.Serve(s, cmd)
+			if code != tc.expectedCode || s.code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d (session: %d)", tc.expectedCode, code, s.code)
+			}
+			if got := s.String(); got != tc.expectedOut {
+				t.Errorf("expected output %q, got %q", tc.expectedOut, got)
+			}
+			// The prompt is written to stderr.
+			if got := strings.TrimPrefix(s.stderr.String(), "Message: "); got != tc.expectedStderr {
+				t.Errorf("expected stderr %q, got %q", tc.expectedStderr, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// StdinIsPiped returns true if input is piped (or redirected from a file) to the configured Reader, i.e. it is not a
+// terminal. Readers that are not files (e.g. a bytes.Buffer in tests) are always treated as piped.
+func (c *Context) StdinIsPiped() bool {
+	f, ok := c.cmd.options().Reader.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// ReadStdin reads all input from the configured Reader. Input that has already been buffered by the prompt helpers
+// (e.g. Prompt) is included.
+func (c *Context) ReadStdin() ([]byte, error) {
+	return ioutil.ReadAll(c.stdin())
+}
+
+// OpenArg opens the file named by the positional argument at index i, or returns the configured Reader if the
+// argument is "-" (which, by convention, means to read from stdin). The caller must close the returned reader, which
+// does not close the Reader. Note that stdin can only be read once, even if "-" is given multiple times.
+func (c *Context) OpenArg(i int) (io.ReadCloser, error) {
+	name := c.Arg(i)
+	if name == "-" {
+		return ioutil.NopCloser(c.stdin()), nil
+	}
+	return os.Open(name)
+}
+
+// ReadArg reads the file named by the positional argument at index i, or stdin if the argument is "-" (see OpenArg).
+func (c *Context) ReadArg(i int) ([]byte, error) {
+	r, err := c.OpenArg(i)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// stdin returns the buffered reader for the configured Reader, which is shared with the prompt helpers.
+func (c *Context) stdin() *bufio.Reader {
+	if c.in == nil {
+		c.in = bufio.NewReader(c.cmd.options().Reader)
+	}
+	return c.in
+}
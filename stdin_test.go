@@ -0,0 +1,71 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestStdinHelpers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte("from file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		args        []string
+		expected    string
+	}{
+		{
+			description: "file argument",
+			args:        []string{path},
+			expected:    "from file",
+		},
+		{
+			description: "dash reads stdin",
+			args:        []string{"-"},
+			expected:    "from stdin\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var called bool
+			c := cli.Command{
+				Usage: "apply <file>",
+				Opts:  cli.Options{Reader: strings.NewReader("from stdin\n")},
+				Exec: func(c *cli.Context) error {
+					called = true
+					eq(t, true, c.StdinIsPiped())
+					b, err := c.ReadArg(0)
+					eq(t, nil, err)
+					eq(t, tc.expected, string(b))
+					return nil
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, true, called)
+		})
+	}
+
+	t.Run("read after prompt", func(t *testing.T) {
+		c := cli.Command{
+			Usage: "apply",
+			Opts:  cli.Options{Reader: strings.NewReader("yes\nrest of\nthe input"), ErrWriter: &strings.Builder{}},
+			Exec: func(c *cli.Context) error {
+				ok, err := c.Confirm("Apply?")
+				eq(t, nil, err)
+				eq(t, true, ok)
+				b, err := c.ReadStdin()
+				eq(t, nil, err)
+				eq(t, "rest of\nthe input", string(b))
+				return nil
+			},
+		}
+		eq(t, nil, c.Execute(nil))
+	})
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Table writes rows of aligned columns. Rows are buffered until Flush is called.
+type Table struct {
+	tw *tabwriter.Writer
+}
+
+// NewTable returns a Table writing to w. The headers (if any) are written as the first row.
+func NewTable(w io.Writer, headers ...string) *Table {
+	t := &Table{tw: tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)}
+	if len(headers) > 0 {
+		fmt.Fprintln(t.tw, strings.Join(headers, "\t"))
+	}
+	return t
+}
+
+// AddRow adds a row to the table, each value is formatted using its default format.
+func (t *Table) AddRow(values ...interface{}) {
+	columns := make([]string, len(values))
+	for i, v := range values {
+		columns[i] = fmt.Sprint(v)
+	}
+	fmt.Fprintln(t.tw, strings.Join(columns, "\t"))
+}
+
+// Flush writes the table to the underlying writer.
+func (t *Table) Flush() error {
+	return t.tw.Flush()
+}
+
+// Table returns a Table that writes to the configured Writer.
+func (c *Context) Table(headers ...string) *Table {
+	return NewTable(c.cmd.options().Writer, headers...)
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Telemetry receives events for the commands that are executed, e.g. to record usage of the CLI in an analytics
+// pipeline. Only the names of the flags that were set are included in the events, never their values, so that secrets
+// passed as flags are not leaked.
+type Telemetry struct {
+	// CommandStarted (optional) is called before the Exec of the command.
+	CommandStarted func(e *TelemetryEvent)
+
+	// CommandFinished (optional) is called after the Exec of the command has returned.
+	CommandFinished func(e *TelemetryEvent)
+}
+
+// TelemetryEvent describes the execution of a command.
+type TelemetryEvent struct {
+	// Command is the complete command path, e.g. "printer repeat".
+	Command string
+
+	// Flags are the (sorted) names of the flags that were set, either in the arguments or by a FlagResolver.
+	Flags []string
+
+	// Started is when the command started executing.
+	Started time.Time
+
+	// Duration of the execution. Only set for CommandFinished.
+	Duration time.Duration
+
+	// Err returned by the command. Only set for CommandFinished.
+	Err error
+}
+
+// start calls CommandStarted for the command, and returns a function that calls CommandFinished with the error
+// returned by the command.
+func (t *Telemetry) start(c *Command) func(error) {
+	if t == nil {
+		return func(error) {}
+	}
+	now := c.options().now
+	e := &TelemetryEvent{Command: c.path(), Started: now()}
+	c.fs.VisitAll(func(f *pflag.Flag) {
+		if _, resolved := c.sources[f.Name]; f.Changed || resolved {
+			e.Flags = append(e.Flags, f.Name)
+		}
+	})
+	sort.Strings(e.Flags)
+
+	if t.CommandStarted != nil {
+		t.CommandStarted(e)
+	}
+	return func(err error) {
+		if t.CommandFinished == nil {
+			return
+		}
+		finished := *e
+		finished.Duration, finished.Err = now().Sub(e.Started), err
+		t.CommandFinished(&finished)
+	}
+}
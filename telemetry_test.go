@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestTelemetry(t *testing.T) {
+	var (
+		started, finished *cli.TelemetryEvent
+		execErr           = errors.New("failed")
+		now               = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	c := cli.Command{
+		Usage: "printer [command]",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "token", EnvVar: []string{"CLI_TEST_UNSET"}}},
+		Opts: cli.Options{
+			Now: func() time.Time {
+				now = now.Add(time.Second)
+				return now
+			},
+			Telemetry: &cli.Telemetry{
+				CommandStarted:  func(e *cli.TelemetryEvent) { started = e },
+				CommandFinished: func(e *cli.TelemetryEvent) { finished = e },
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Usage: "repeat [flags] <text>",
+				Flags: []cli.Flag{&cli.IntFlag{Name: "count, n"}},
+				Exec:  func(c *cli.Context) error { return execErr },
+			},
+		},
+	}
+	err := c.Execute([]string{"--token=secret", "repeat", "-n", "3", "hello"})
+	eq(t, execErr, err)
+
+	if started == nil || finished == nil {
+		t.Fatal("expected both events")
+	}
+	eq(t, "printer repeat", started.Command)
+	eq(t, []string{"count", "token"}, started.Flags)
+	eq(t, nil, started.Err)
+	eq(t, "printer repeat", finished.Command)
+	eq(t, []string{"count", "token"}, finished.Flags)
+	eq(t, execErr, finished.Err)
+	eq(t, started.Started, finished.Started)
+	eq(t, time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC), started.Started)
+	eq(t, time.Second, finished.Duration)
+}
+
+func TestTelemetry_ResolvedFlags(t *testing.T) {
+	var (
+		started *cli.TelemetryEvent
+		names   []string
+	)
+	c := cli.Command{
+		Usage: "deploy",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "region", EnvVar: []string{"CLI_TEST_REGION"}},
+			&cli.BoolFlag{Name: "force"},
+		},
+		Opts: cli.Options{
+			Environ:   []string{"CLI_TEST_REGION=eu-west-1"},
+			Telemetry: &cli.Telemetry{CommandStarted: func(e *cli.TelemetryEvent) { started = e }},
+		},
+		Exec: func(c *cli.Context) error {
+			names = c.FlagNames()
+			return nil
+		},
+	}
+	eq(t, nil, c.Execute([]string{}))
+	eq(t, []string{"region"}, started.Flags)
+	eq(t, names, started.Flags)
+}
@@ -0,0 +1,45 @@
+package cli
+
+import "fmt"
+
+// tracing returns true if parse tracing is enabled by the Options.TraceEnvVar.
+func (c *Command) tracing() bool {
+	env := c.options().TraceEnvVar
+	if env == "" {
+		return false
+	}
+	v := c.options().getenv(env)
+	return v != "" && v != "0"
+}
+
+// tracef prints a trace message for the command to the ErrWriter, if tracing is enabled.
+func (c *Command) tracef(format string, args ...interface{}) {
+	if !c.tracing() {
+		return
+	}
+	fmt.Fprintf(c.options().ErrWriter, "trace: %s: "+format+"\n", append([]interface{}{c.path()}, args...)...)
+}
+
+// traceRewrite traces the arguments if they were rewritten by a step of the parsing (e.g. alias expansion).
+func (c *Command) traceRewrite(step string, before, after []string) {
+	if c.tracing() && fmt.Sprintf("%q", before) != fmt.Sprintf("%q", after) {
+		c.tracef("%s: %q -> %q", step, before, after)
+	}
+}
+
+// traceResolved traces the outcome of resolving the flags that were not set by the arguments.
+func (c *Command) traceResolved(flags []Flag, sources map[string]string) {
+	if !c.tracing() {
+		return
+	}
+	for _, f := range flags {
+		pf := c.fs.Lookup(f.GetName())
+		switch source := sources[f.GetName()]; {
+		case pf == nil || source == "arg":
+		case source != "":
+			c.tracef("flag --%s resolved from %s", f.GetName(), source)
+		default:
+			c.tracef("flag --%s not resolved, using the default %q", f.GetName(), pf.DefValue)
+		}
+	}
+}
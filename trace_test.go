@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestTraceEnvVar(t *testing.T) {
+	t.Setenv("APP_REGION", "eu-west-1")
+
+	tests := []struct {
+		description string
+		trace       string
+		expected    string
+	}{
+		{
+			description: "enabled",
+			trace:       "1",
+			expected: `trace: app: arguments ["d" "-fv" "x"]
+trace: app: expanded alias: ["d" "-fv" "x"] -> ["deploy" "-fv" "x"]
+trace: app: argument 0 selects subcommand "deploy"
+trace: app deploy: selected for execution
+trace: app deploy: split shorthands: ["-fv" "x"] -> ["-f" "-v" "x"]
+trace: app deploy: flag --force bound to "true"
+trace: app deploy: flag --version bound to "x"
+trace: app deploy: positional arguments []
+trace: app deploy: flag --timeout not resolved, using the default "30s"
+trace: app deploy: flag --region resolved from $APP_REGION
+`,
+		},
+		{
+			description: "disabled",
+			trace:       "0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Setenv("APP_TRACE", tc.trace)
+
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage: "app [command]",
+				Flags: []cli.Flag{&cli.StringFlag{Name: "region", EnvVar: []string{"APP_REGION"}}},
+				Opts: cli.Options{
+					TraceEnvVar: "APP_TRACE",
+					Aliases:     map[string]string{"d": "deploy"},
+					ErrWriter:   &b,
+				},
+				Subcommands: []*cli.Command{
+					{
+						Usage: "deploy [flags]",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{Name: "force, f"},
+							&cli.StringFlag{Name: "version, v"},
+							&cli.DurationFlag{Name: "timeout", Value: 30 * time.Second},
+						},
+						Exec: func(c *cli.Context) error { return nil },
+					},
+				},
+			}
+			eq(t, nil, c.Execute([]string{"d", "-fv", "x"}))
+			eq(t, tc.expected, b.String())
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// IsStdinTTY returns true if the configured Reader is a terminal.
+func (c *Context) IsStdinTTY() bool {
+	return isTerminal(c.cmd.options().Reader)
+}
+
+// IsStdoutTTY returns true if the configured Writer is a terminal.
+func (c *Context) IsStdoutTTY() bool {
+	return isTerminal(c.cmd.options().Writer)
+}
+
+// IsStderrTTY returns true if the configured ErrWriter is a terminal.
+func (c *Context) IsStderrTTY() bool {
+	return isTerminal(c.cmd.options().ErrWriter)
+}
+
+// isTerminal returns true if the stream is a file descriptor connected to a terminal.
+func isTerminal(stream interface{}) bool {
+	f, ok := stream.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpdateChecker checks for new versions of the application while a command is executing, and prints an upgrade hint
+// to the ErrWriter when the command has completed. The check is skipped if the root command has no Version.
+type UpdateChecker struct {
+	// Latest returns the latest available version, e.g. by querying GitHub releases.
+	Latest func(ctx context.Context) (string, error)
+
+	// TTL is how long the latest version is cached before checking again. Defaults to 24 hours.
+	TTL time.Duration
+
+	// Timeout is how long to wait for the check after the command has completed. Defaults to 1 second.
+	Timeout time.Duration
+
+	// CacheFile is where the latest version is cached. Defaults to <user cache dir>/<name>/update-check.json.
+	CacheFile string
+
+	// DisableEnvVar is an environment variable that disables the check when it is set to a non-empty value.
+	// Defaults to <NAME>_NO_UPDATE_CHECK, where NAME is the name of the root command.
+	DisableEnvVar string
+}
+
+// updateCache is the format of the UpdateChecker.CacheFile.
+type updateCache struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Latest    string    `json:"latest"`
+}
+
+// start begins checking for a newer version in the background, and returns a function that waits for the check to
+// complete (or time out) and prints the upgrade hint.
+func (u *UpdateChecker) start(c *Command) func() {
+	if u == nil || u.Latest == nil {
+		return func() {}
+	}
+	info := c.VersionInfo()
+	if info.Version == "" || u.disabled(c.options(), info.Name) {
+		return func() {}
+	}
+	timeout := u.Timeout
+	if timeout == 0 {
+		timeout = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan string, 1)
+	go func() {
+		latest, err := u.latest(ctx, c.options(), info.Name)
+		if err != nil {
+			latest = ""
+		}
+		result <- latest
+	}()
+
+	return func() {
+		defer cancel()
+		select {
+		case latest := <-result:
+			if latest != "" && newerVersion(info.Version, latest) {
+				fmt.Fprintf(c.options().ErrWriter, "\n"+c.tr("A new version of %s is available: %s -> %s")+"\n", info.Name, info.Version, latest)
+			}
+		case <-time.After(timeout):
+		}
+	}
+}
+
+// disabled returns true if the opt-out environment variable is set.
+func (u *UpdateChecker) disabled(opts *Options, name string) bool {
+	return opts.getenv(u.disableEnvVar(name)) != ""
+}
+
+// disableEnvVar returns the name of the opt-out environment variable for the application (see DisableEnvVar).
+func (u *UpdateChecker) disableEnvVar(name string) string {
+	if u.DisableEnvVar != "" {
+		return u.DisableEnvVar
+	}
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_NO_UPDATE_CHECK"
+}
+
+// latest returns the cached latest version if it is still valid, or calls Latest and updates the cache.
+func (u *UpdateChecker) latest(ctx context.Context, opts *Options, name string) (string, error) {
+	ttl := u.TTL
+	if ttl == 0 {
+		ttl = 24 * time.Hour
+	}
+	path := u.CacheFile
+	if path == "" {
+		dir, err := opts.userCacheDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, name, "update-check.json")
+	}
+
+	var cache updateCache
+	if b, err := ioutil.ReadFile(path); err == nil && json.Unmarshal(b, &cache) == nil {
+		if opts.now().Sub(cache.CheckedAt) < ttl {
+			return cache.Latest, nil
+		}
+	}
+
+	latest, err := u.Latest(ctx)
+	if err != nil {
+		return "", err
+	}
+	if b, err := json.Marshal(updateCache{CheckedAt: opts.now(), Latest: latest}); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			ioutil.WriteFile(path, b, 0o644) // Failing to cache is not an error.
+		}
+	}
+	return latest, nil
+}
+
+// newerVersion returns true if latest is a newer version than current. Versions are compared as dot-separated
+// numbers (with an optional "v" prefix), and any pre-release or build suffix is ignored.
+func newerVersion(current, latest string) bool {
+	c, l := parseVersion(current), parseVersion(latest)
+	for i := 0; i < len(c) || i < len(l); i++ {
+		var cv, lv int
+		if i < len(c) {
+			cv = c[i]
+		}
+		if i < len(l) {
+			lv = l[i]
+		}
+		if cv != lv {
+			return lv > cv
+		}
+	}
+	return false
+}
+
+// parseVersion returns the numeric parts of a version string.
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
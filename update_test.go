@@ -0,0 +1,47 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestUpdateChecker(t *testing.T) {
+	var (
+		b     bytes.Buffer
+		calls int
+	)
+	c := cli.Command{
+		Usage:   "printer",
+		Version: "v1.2.3",
+		Exec:    func(c *cli.Context) error { return nil },
+		Opts: cli.Options{
+			ErrWriter: &b,
+			UpdateChecker: &cli.UpdateChecker{
+				Latest: func(context.Context) (string, error) {
+					calls++
+					return "v1.10.0", nil
+				},
+				CacheFile: filepath.Join(t.TempDir(), "update-check.json"),
+			},
+		},
+	}
+
+	eq(t, nil, c.Execute(nil))
+	eq(t, "\nA new version of printer is available: v1.2.3 -> v1.10.0\n", b.String())
+
+	b.Reset()
+	eq(t, nil, c.Execute(nil))
+	eq(t, "\nA new version of printer is available: v1.2.3 -> v1.10.0\n", b.String())
+	eq(t, 1, calls)
+
+	b.Reset()
+	os.Setenv("PRINTER_NO_UPDATE_CHECK", "1")
+	defer os.Unsetenv("PRINTER_NO_UPDATE_CHECK")
+	eq(t, nil, c.Execute(nil))
+	eq(t, "", b.String())
+}
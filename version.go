@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/template"
+)
+
+// versionFlagName is the name of the flag added to the root command when Command.Version is set.
+const versionFlagName = "version"
+
+// errVersion is returned by parse when the version flag is set.
+var errVersion = errors.New("version requested")
+
+// VersionInfo describes the version of the application.
+type VersionInfo struct {
+	Name      string `json:"name" yaml:"name"`
+	Version   string `json:"version" yaml:"version"`
+	Commit    string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Date      string `json:"date,omitempty" yaml:"date,omitempty"`
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+	Platform  string `json:"platform" yaml:"platform"`
+}
+
+// VersionInfo returns the version information for the application. The commit and date are read from the build
+// information embedded in the binary (if available).
+func (c *Command) VersionInfo() VersionInfo {
+	root := c.root()
+	info := VersionInfo{
+		Name:      root.name(),
+		Version:   root.Version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.time":
+				info.Date = s.Value
+			}
+		}
+	}
+	return info
+}
+
+// VersionTemplate returns a function that can be used as Options.VersionFunc, which renders the VersionInfo using
+// the given Go template. It panics if the template is invalid.
+func VersionTemplate(text string) func(*Command) string {
+	t := template.Must(template.New("version").Parse(text))
+	return func(c *Command) string {
+		var b strings.Builder
+		if err := t.Execute(&b, c.VersionInfo()); err != nil {
+			return fmt.Sprintf("error rendering version: %s", err)
+		}
+		return b.String()
+	}
+}
+
+// defaultVersionFunc is the default function used to produce the version string that is printed when --version or
+// the version subcommand is used. It is the default value for VersionFunc in Options.
+func defaultVersionFunc(c *Command) string {
+	info := c.VersionInfo()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s version %s\n", info.Name, info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(&b, "commit: %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Fprintf(&b, "date: %s\n", info.Date)
+	}
+	fmt.Fprintf(&b, "go: %s (%s)", info.GoVersion, info.Platform)
+	return b.String()
+}
+
+// printVersion writes the version information to w. If the command has an OutputFlag set to json or yaml, the
+// VersionInfo is encoded in that format instead.
+func (c *Command) printVersion(w io.Writer) error {
+	if format, _ := splitOutputFormat(c.outputFormat()); format == OutputJSON || format == OutputYAML {
+		return encode(w, format, c.VersionInfo())
+	}
+	_, err := fmt.Fprintln(w, strings.TrimSuffix(c.options().VersionFunc(c), "\n"))
+	return err
+}
+
+// versionRequested returns true if this is the root command and the version flag was set.
+func (c *Command) versionRequested() bool {
+	if c.parent != nil || c.Version == "" {
+		return false
+	}
+	v, err := c.fs.GetBool(versionFlagName)
+	return err == nil && v
+}
+
+// versionFlag returns the --version flag.
+func versionFlag() Flag {
+	return &BoolFlag{
+		Name:  versionFlagName,
+		Usage: "Print version information and exit",
+	}
+}
+
+// versionCommand returns the version subcommand.
+func versionCommand() *Command {
+	return &Command{
+		Usage: "version",
+		Help:  "Print version information",
+		Exec: func(c *Context) error {
+			return c.cmd.printVersion(c.cmd.options().Writer)
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/itsdalmo/cli"
+)
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+	}{
+		{
+			description: "flag",
+			args:        []string{"--version"},
+		},
+		{
+			description: "subcommand",
+			args:        []string{"version"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage:   "printer [flags] [command]",
+				Version: "1.2.3",
+				Subcommands: []*cli.Command{
+					{
+						Usage: "echo",
+						Exec:  func(c *cli.Context) error { return nil },
+					},
+				},
+				Opts: cli.Options{
+					Writer: &b,
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, "printer version 1.2.3\ngo: "+runtime.Version()+" ("+runtime.GOOS+"/"+runtime.GOARCH+")\n", b.String())
+		})
+	}
+}
+
+func TestVersionOutput(t *testing.T) {
+	tests := []struct {
+		description string
+		args        []string
+		versionFunc func(*cli.Command) string
+		expected    string
+	}{
+		{
+			description: "template",
+			args:        []string{"--version"},
+			versionFunc: cli.VersionTemplate("{{ .Name }} {{ .Version }} (stable)"),
+			expected:    "printer 1.2.3 (stable)\n",
+		},
+		{
+			description: "json",
+			args:        []string{"--version", "--output", "json"},
+			expected: fmt.Sprintf(`{
+  "name": "printer",
+  "version": "1.2.3",
+  "goVersion": "%s",
+  "platform": "%s/%s"
+}
+`, runtime.Version(), runtime.GOOS, runtime.GOARCH),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var b bytes.Buffer
+			c := cli.Command{
+				Usage:   "printer [flags]",
+				Version: "1.2.3",
+				Flags: []cli.Flag{
+					&cli.OutputFlag{},
+				},
+				Exec: func(c *cli.Context) error { return nil },
+				Opts: cli.Options{
+					Writer:      &b,
+					VersionFunc: tc.versionFunc,
+				},
+			}
+			eq(t, nil, c.Execute(tc.args))
+			eq(t, tc.expected, b.String())
+		})
+	}
+}